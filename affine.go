@@ -0,0 +1,165 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// AffineMatrix is a 3D affine transformation, same as the parameters of
+// ST_Affine. A point is transformed as
+//
+//	x' = A*x + B*y + C*z + XOff
+//	y' = D*x + E*y + F*z + YOff
+//	z' = G*x + H*y + I*z + ZOff
+//
+// Z is taken as 0 for points without Z, and M is never changed.
+type AffineMatrix struct {
+	A, B, C, D, E, F, G, H, I float64
+	XOff, YOff, ZOff          float64
+}
+
+// IdentityMatrix leaves every point unchanged.
+func IdentityMatrix() AffineMatrix {
+	return AffineMatrix{A: 1, E: 1, I: 1}
+}
+
+// Translate moves points by dx, dy and dz, same as ST_Translate.
+func Translate(dx, dy, dz float64) AffineMatrix {
+	m := IdentityMatrix()
+	m.XOff, m.YOff, m.ZOff = dx, dy, dz
+	return m
+}
+
+// Scale multiplies coordinates by sx, sy and sz, same as ST_Scale. M is
+// left unchanged, unlike ST_Scale with an M factor.
+func Scale(sx, sy, sz float64) AffineMatrix {
+	return AffineMatrix{A: sx, E: sy, I: sz}
+}
+
+// Rotate rotates points counter clockwise by angle radians around the
+// origin, same as ST_Rotate.
+func Rotate(angle float64) AffineMatrix {
+	sin, cos := math.Sincos(angle)
+	return AffineMatrix{A: cos, B: -sin, D: sin, E: cos, I: 1}
+}
+
+// RotateAround rotates points counter clockwise by angle radians around
+// x, y, same as ST_Rotate with an origin.
+func RotateAround(angle, x, y float64) AffineMatrix {
+	return Translate(-x, -y, 0).Then(Rotate(angle)).Then(Translate(x, y, 0))
+}
+
+// Then returns the transformation applying m followed by n.
+func (m AffineMatrix) Then(n AffineMatrix) AffineMatrix {
+	return AffineMatrix{
+		A:    n.A*m.A + n.B*m.D + n.C*m.G,
+		B:    n.A*m.B + n.B*m.E + n.C*m.H,
+		C:    n.A*m.C + n.B*m.F + n.C*m.I,
+		D:    n.D*m.A + n.E*m.D + n.F*m.G,
+		E:    n.D*m.B + n.E*m.E + n.F*m.H,
+		F:    n.D*m.C + n.E*m.F + n.F*m.I,
+		G:    n.G*m.A + n.H*m.D + n.I*m.G,
+		H:    n.G*m.B + n.H*m.E + n.I*m.H,
+		I:    n.G*m.C + n.H*m.F + n.I*m.I,
+		XOff: n.A*m.XOff + n.B*m.YOff + n.C*m.ZOff + n.XOff,
+		YOff: n.D*m.XOff + n.E*m.YOff + n.F*m.ZOff + n.YOff,
+		ZOff: n.G*m.XOff + n.H*m.YOff + n.I*m.ZOff + n.ZOff,
+	}
+}
+
+// Apply transforms geometry g in place. g is a pointer to a point type,
+// or a slice, possibly nested, of point types. NULL and EMPTY points are
+// left unchanged.
+func (m AffineMatrix) Apply(g any) error {
+	v := reflect.ValueOf(g)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return applyPoints(v, m.transform)
+}
+
+// Affine returns a transformed copy of geometry g, which is any point type
+// or a slice, possibly nested, of point types. g is not changed.
+func Affine[G any](g G, m AffineMatrix) (G, error) {
+	v := reflect.ValueOf(&g).Elem()
+	c := reflect.New(v.Type()).Elem()
+	c.Set(deepCopy(v))
+
+	if err := applyPoints(c, m.transform); err != nil {
+		var zero G
+		return zero, err
+	}
+
+	return c.Interface().(G), nil
+}
+
+// Copies the slices of v, points are copied by value.
+func deepCopy(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Slice || v.IsNil() {
+		return v
+	}
+
+	c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		c.Index(i).Set(deepCopy(v.Index(i)))
+	}
+
+	return c
+}
+
+type pointDataSetter interface {
+	data() pointData
+	setData(pointData)
+}
+
+// Replaces every point d of v with fn(d).
+func applyPoints(v reflect.Value, fn func(pointData) pointData) error {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return fmt.Errorf("nil geometry")
+	}
+
+	if v.CanAddr() {
+		if p, ok := v.Addr().Interface().(pointDataSetter); ok {
+			p.setData(fn(p.data()))
+			return nil
+		}
+	}
+
+	if v.Type().Implements(pointDataType) {
+		return fmt.Errorf("point can not be changed in place, use a pointer. got: %v", v.Type())
+	}
+
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("geometry type not supported. got: %v", v.Type())
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := applyPoints(v.Index(i), fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m AffineMatrix) transform(d pointData) pointData {
+	if !d.valid || d.empty {
+		return d
+	}
+
+	hasZ := d.layout.flags&ewkbZFlag != 0
+	x, y, z := d.coords[0], d.coords[1], 0.0
+	if hasZ {
+		z = d.coords[2]
+	}
+
+	d.coords[0] = m.A*x + m.B*y + m.C*z + m.XOff
+	d.coords[1] = m.D*x + m.E*y + m.F*z + m.YOff
+	if hasZ {
+		d.coords[2] = m.G*x + m.H*y + m.I*z + m.ZOff
+	}
+
+	return d
+}
@@ -0,0 +1,108 @@
+package gopostgis_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestAffineMatrix(t *testing.T) {
+	near := func(a, b gopostgis.PointZMS) bool {
+		return a.SRID == b.SRID && a.Valid == b.Valid && a.Empty == b.Empty && a.M == b.M &&
+			math.Abs(a.X-b.X) < 1e-12 && math.Abs(a.Y-b.Y) < 1e-12 && math.Abs(a.Z-b.Z) < 1e-12
+	}
+
+	p := gopostgis.PointZMS{SRID: 3857, X: 1, Y: 2, Z: 3, M: 7, Valid: true}
+	cases := []struct {
+		name     string
+		m        gopostgis.AffineMatrix
+		expected gopostgis.PointZMS
+	}{
+		{"identity", gopostgis.IdentityMatrix(), p},
+		{"translate", gopostgis.Translate(10, -1, 0.5), gopostgis.PointZMS{SRID: 3857, X: 11, Y: 1, Z: 3.5, M: 7, Valid: true}},
+		{"scale", gopostgis.Scale(2, 3, 4), gopostgis.PointZMS{SRID: 3857, X: 2, Y: 6, Z: 12, M: 7, Valid: true}},
+		{"rotate", gopostgis.Rotate(math.Pi / 2), gopostgis.PointZMS{SRID: 3857, X: -2, Y: 1, Z: 3, M: 7, Valid: true}},
+		{"rotate around", gopostgis.RotateAround(math.Pi, 1, 1), gopostgis.PointZMS{SRID: 3857, X: 1, Y: 0, Z: 3, M: 7, Valid: true}},
+		{"then", gopostgis.Scale(2, 2, 2).Then(gopostgis.Translate(1, 1, 1)), gopostgis.PointZMS{SRID: 3857, X: 3, Y: 5, Z: 7, M: 7, Valid: true}},
+		{"affine", gopostgis.AffineMatrix{A: 1, B: 1, C: 1, E: 1, I: 1, XOff: 1}, gopostgis.PointZMS{SRID: 3857, X: 7, Y: 2, Z: 3, M: 7, Valid: true}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			found, err := gopostgis.Affine(p, c.m)
+			if err != nil {
+				t.Fatal("expected: nil found:", err)
+			}
+			if !near(found, c.expected) {
+				t.Error("expected:", c.expected, "found:", found)
+			}
+
+			inPlace := p
+			if err := c.m.Apply(&inPlace); err != nil {
+				t.Fatal("expected: nil found:", err)
+			}
+			if !near(inPlace, c.expected) {
+				t.Error("expected:", c.expected, "found:", inPlace)
+			}
+		})
+	}
+
+	t.Run("without z", func(t *testing.T) {
+		m := gopostgis.AffineMatrix{A: 1, C: 5, E: 1, I: 1, ZOff: 9}
+		found, err := gopostgis.Affine(gopostgis.PointM{X: 1, Y: 2, M: 3, Valid: true}, m)
+		expected := gopostgis.PointM{X: 1, Y: 2, M: 3, Valid: true}
+		if err != nil || found != expected {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		polygon := [][]gopostgis.Point{xyLine(0, 0, 1, 0, 0, 1, 0, 0), nil}
+		found, err := gopostgis.Affine(polygon, gopostgis.Translate(1, 1, 0))
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+
+		expected := [][]gopostgis.Point{xyLine(1, 1, 2, 1, 1, 2, 1, 1), nil}
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+		if original := xyLine(0, 0, 1, 0, 0, 1, 0, 0); !reflect.DeepEqual(polygon[0], original) {
+			t.Error("expected:", original, "found:", polygon[0])
+		}
+	})
+
+	t.Run("in place", func(t *testing.T) {
+		line := []gopostgis.PointS{
+			{SRID: 4326, X: 1, Y: 1, Valid: true},
+			{SRID: 4326},
+			{SRID: 4326, Empty: true, Valid: true},
+		}
+		if err := gopostgis.Scale(2, 2, 1).Apply(line); err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		expected := []gopostgis.PointS{
+			{SRID: 4326, X: 2, Y: 2, Valid: true},
+			{SRID: 4326},
+			{SRID: 4326, Empty: true, Valid: true},
+		}
+		if !reflect.DeepEqual(line, expected) {
+			t.Error("expected:", expected, "found:", line)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		m := gopostgis.IdentityMatrix()
+		if err := m.Apply(gopostgis.Point{Valid: true}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if err := m.Apply(nil); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.Affine([]string{"x"}, m); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package gopostgis
+
+import "fmt"
+
+// Planar boolean operations over multi polygons, same as ST_Union,
+// ST_Intersection, ST_Difference and ST_SymDifference on polygonal input.
+// A polygon is a multi polygon of one element and a nil multi polygon is
+// empty. Results have clockwise exterior rings and counter clockwise
+// interior rings, in the SRID of the input, without Z and M. Results of
+// lower dimension like shared edges or touching vertices are dropped.
+
+// Union returns the area covered by a or b.
+func Union[T PointType, P interface {
+	*T
+	setData(pointData)
+}](a, b [][][]T) ([][][]T, error) {
+	return polygonOverlay[T, P](a, b, overlayUnion)
+}
+
+// Intersection returns the area covered by both a and b.
+func Intersection[T PointType, P interface {
+	*T
+	setData(pointData)
+}](a, b [][][]T) ([][][]T, error) {
+	return polygonOverlay[T, P](a, b, overlayIntersection)
+}
+
+// Difference returns the area covered by a but not by b.
+func Difference[T PointType, P interface {
+	*T
+	setData(pointData)
+}](a, b [][][]T) ([][][]T, error) {
+	return polygonOverlay[T, P](a, b, overlayDifference)
+}
+
+// SymDifference returns the area covered by exactly one of a and b.
+func SymDifference[T PointType, P interface {
+	*T
+	setData(pointData)
+}](a, b [][][]T) ([][][]T, error) {
+	return polygonOverlay[T, P](a, b, overlaySymDifference)
+}
+
+func polygonOverlay[T PointType, P interface {
+	*T
+	setData(pointData)
+}](a, b [][][]T, op overlayOp) ([][][]T, error) {
+	var pieces []overlayPiece
+	var srid uint32
+	first := true
+
+	for group, multi := range [2][][][]T{a, b} {
+		for _, polygon := range multi {
+			for _, r := range polygon {
+				for _, p := range r {
+					d := p.data()
+					if !d.valid || d.empty {
+						return nil, fmt.Errorf("polygon with NULL or EMPTY point")
+					}
+					if _, err := checkFinite(d.values()...); err != nil {
+						return nil, err
+					}
+					if first {
+						srid, first = d.srid, false
+					} else if d.srid != srid {
+						return nil, fmt.Errorf("mixed srid. got: %d and %d", d.srid, srid)
+					}
+				}
+			}
+
+			if p := newOverlayPiece(polygonXY(polygon), group); len(p.rings) > 0 {
+				pieces = append(pieces, p)
+			}
+		}
+	}
+
+	return toMultiPolygon[T, P](overlay(pieces, op), srid), nil
+}
@@ -0,0 +1,276 @@
+package gopostgis_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func box(minX, minY, maxX, maxY float64) [][][]gopostgis.Point {
+	return [][][]gopostgis.Point{{xyLine(minX, minY, maxX, minY, maxX, maxY, minX, maxY, minX, minY)}}
+}
+
+func TestBooleanOperations(t *testing.T) {
+	type operation func(a, b [][][]gopostgis.Point) ([][][]gopostgis.Point, error)
+	ops := []struct {
+		name string
+		fn   operation
+	}{
+		{"union", gopostgis.Union[gopostgis.Point]},
+		{"intersection", gopostgis.Intersection[gopostgis.Point]},
+		{"difference", gopostgis.Difference[gopostgis.Point]},
+		{"sym difference", gopostgis.SymDifference[gopostgis.Point]},
+	}
+
+	// expected number of polygons and area for each of ops
+	cases := []struct {
+		name     string
+		a, b     [][][]gopostgis.Point
+		polygons [4]int
+		area     [4]float64
+	}{
+		{
+			name:     "overlapping",
+			a:        box(0, 0, 2, 2),
+			b:        box(1, 1, 3, 3),
+			polygons: [4]int{1, 1, 1, 2},
+			area:     [4]float64{7, 1, 3, 6},
+		},
+		{
+			name:     "shared edge",
+			a:        box(0, 0, 1, 1),
+			b:        box(1, 0, 2, 1),
+			polygons: [4]int{1, 0, 1, 1},
+			area:     [4]float64{2, 0, 1, 2},
+		},
+		{
+			name:     "partially shared edge",
+			a:        box(0, 0, 2, 2),
+			b:        box(2, 1, 3, 4),
+			polygons: [4]int{1, 0, 1, 1},
+			area:     [4]float64{7, 0, 4, 7},
+		},
+		{
+			name:     "touching vertices",
+			a:        box(0, 0, 1, 1),
+			b:        box(1, 1, 2, 2),
+			polygons: [4]int{2, 0, 1, 2},
+			area:     [4]float64{2, 0, 1, 2},
+		},
+		{
+			name:     "identical",
+			a:        box(0, 0, 1, 1),
+			b:        box(0, 0, 1, 1),
+			polygons: [4]int{1, 1, 0, 0},
+			area:     [4]float64{1, 1, 0, 0},
+		},
+		{
+			name:     "disjoint",
+			a:        box(0, 0, 1, 1),
+			b:        box(5, 5, 6, 6),
+			polygons: [4]int{2, 0, 1, 2},
+			area:     [4]float64{2, 0, 1, 2},
+		},
+		{
+			name:     "contained",
+			a:        box(0, 0, 4, 4),
+			b:        box(1, 1, 2, 2),
+			polygons: [4]int{1, 1, 1, 1},
+			area:     [4]float64{16, 1, 15, 15},
+		},
+		{
+			name:     "contained touching boundary",
+			a:        box(0, 0, 4, 4),
+			b:        box(0, 0, 2, 2),
+			polygons: [4]int{1, 1, 1, 1},
+			area:     [4]float64{16, 4, 12, 12},
+		},
+		{
+			name:     "empty operand",
+			a:        box(0, 0, 1, 1),
+			b:        nil,
+			polygons: [4]int{1, 0, 1, 1},
+			area:     [4]float64{1, 0, 1, 1},
+		},
+		{
+			name:     "multi polygon",
+			a:        append(box(0, 0, 2, 2), box(4, 0, 6, 2)...),
+			b:        box(1, 0, 5, 1),
+			polygons: [4]int{1, 2, 2, 3},
+			area:     [4]float64{10, 2, 6, 8},
+		},
+	}
+
+	for _, c := range cases {
+		for i, op := range ops {
+			t.Run(c.name+" "+op.name, func(t *testing.T) {
+				found, err := op.fn(c.a, c.b)
+				if err != nil {
+					t.Fatal("expected: nil found:", err)
+				}
+				if len(found) != c.polygons[i] {
+					t.Error("expected:", c.polygons[i], "found:", found)
+				}
+				if a := polygonsArea(found); math.Abs(a-c.area[i]) > 1e-9 {
+					t.Error("expected:", c.area[i], "found:", a)
+				}
+				for _, p := range found {
+					for j, r := range p {
+						if r[0] != r[len(r)-1] {
+							t.Error("expected: closed ring found:", r)
+						}
+						if cw := ringArea(r) < 0; cw != (j == 0) {
+							t.Error("expected: clockwise shell and counter clockwise holes found:", p)
+						}
+					}
+				}
+			})
+		}
+	}
+
+	t.Run("difference makes a hole", func(t *testing.T) {
+		found, err := gopostgis.Difference(box(0, 0, 4, 4), box(1, 1, 2, 2))
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 2 || ringArea(found[0][1]) != 1 {
+			t.Error("expected: polygon with a hole found:", found)
+		}
+	})
+
+	t.Run("union fills a hole", func(t *testing.T) {
+		donut := [][][]gopostgis.Point{{
+			xyLine(0, 0, 4, 0, 4, 4, 0, 4, 0, 0),
+			xyLine(1, 1, 1, 3, 3, 3, 3, 1, 1, 1),
+		}}
+		found, err := gopostgis.Union(donut, box(1, 1, 3, 3))
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 1 || polygonsArea(found) != 16 {
+			t.Error("expected: 4x4 square found:", found)
+		}
+	})
+
+	t.Run("keeps srid", func(t *testing.T) {
+		square := func(x float64) [][][]gopostgis.PointS {
+			return [][][]gopostgis.PointS{{{
+				{SRID: 3857, X: x, Y: 0, Valid: true},
+				{SRID: 3857, X: x + 2, Y: 0, Valid: true},
+				{SRID: 3857, X: x + 2, Y: 2, Valid: true},
+				{SRID: 3857, X: x, Y: 2, Valid: true},
+				{SRID: 3857, X: x, Y: 0, Valid: true},
+			}}}
+		}
+		found, err := gopostgis.Intersection(square(0), square(1))
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || found[0][0][0].SRID != 3857 {
+			t.Error("expected: srid 3857 found:", found)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		mixed := [][][]gopostgis.PointS{{{
+			{SRID: 4326, X: 0, Y: 0, Valid: true},
+			{SRID: 3857, X: 1, Y: 0, Valid: true},
+			{SRID: 4326, X: 0, Y: 1, Valid: true},
+		}}}
+		if _, err := gopostgis.Union(mixed, nil); err == nil {
+			t.Error("expected: error found:", err)
+		}
+
+		null := [][][]gopostgis.Point{{{{X: 0, Y: 0}, {X: 1, Y: 0, Valid: true}, {X: 0, Y: 1, Valid: true}}}}
+		if _, err := gopostgis.Difference(box(0, 0, 1, 1), null); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestBooleanAreaIdentities(t *testing.T) {
+	// regular polygon of n sides around x, y rotated by angle
+	regular := func(n int, x, y, r, angle float64) [][][]gopostgis.Point {
+		ring := make([]gopostgis.Point, n+1)
+		for i := 0; i < n; i++ {
+			a := angle + 2*math.Pi*float64(i)/float64(n)
+			ring[i] = gopostgis.Point{X: x + r*math.Cos(a), Y: y + r*math.Sin(a), Valid: true}
+		}
+		ring[n] = ring[0]
+		return [][][]gopostgis.Point{{ring}}
+	}
+
+	for i := 0; i < 20; i++ {
+		a := regular(3+i%5, 0, 0, 10, 0.1*float64(i))
+		b := regular(4+i%7, 3+0.3*float64(i), 2, 8, 0.7*float64(i))
+
+		union, err1 := gopostgis.Union(a, b)
+		inter, err2 := gopostgis.Intersection(a, b)
+		diff, err3 := gopostgis.Difference(a, b)
+		sym, err4 := gopostgis.SymDifference(a, b)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			t.Fatal("expected: nil found:", err1, err2, err3, err4)
+		}
+
+		areaA, areaB := polygonsArea(a), polygonsArea(b)
+		if areaA < 0 {
+			areaA, areaB = -areaA, -areaB
+		}
+		u, n, d, s := polygonsArea(union), polygonsArea(inter), polygonsArea(diff), polygonsArea(sym)
+		if math.Abs(u+n-areaA-areaB) > 1e-6 || math.Abs(d+n-areaA) > 1e-6 || math.Abs(s+n-u) > 1e-6 {
+			t.Error("expected: consistent areas found:", u, n, d, s, areaA, areaB)
+		}
+	}
+}
+
+// Random star shaped polygon around x, y with vertices snapped to a grid
+// of 0.25, so rings of different polygons often share vertices.
+func randomStar(r *rand.Rand, x, y float64) [][]gopostgis.Point {
+	n := 5 + r.Intn(6)
+	angles := make([]float64, n)
+	for i := range angles {
+		angles[i] = 2 * math.Pi * (float64(i) + r.Float64()) / float64(n)
+	}
+
+	ring := make([]gopostgis.Point, 0, n+1)
+	for _, a := range angles {
+		d := 1 + 4*r.Float64()
+		px := math.Round((x+d*math.Cos(a))*4) / 4
+		py := math.Round((y+d*math.Sin(a))*4) / 4
+		ring = append(ring, gopostgis.Point{X: px, Y: py, Valid: true})
+	}
+
+	return [][]gopostgis.Point{append(ring, ring[0])}
+}
+
+func TestBooleanValidity(t *testing.T) {
+	ops := map[string]func(a, b [][][]gopostgis.Point) ([][][]gopostgis.Point, error){
+		"union":          gopostgis.Union[gopostgis.Point],
+		"intersection":   gopostgis.Intersection[gopostgis.Point],
+		"difference":     gopostgis.Difference[gopostgis.Point],
+		"sym difference": gopostgis.SymDifference[gopostgis.Point],
+	}
+
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 2000; i++ {
+		a := randomStar(r, 0, 0)
+		b := randomStar(r, 4*r.Float64()-2, 4*r.Float64()-2)
+		if !gopostgis.IsValidPolygon(a) || !gopostgis.IsValidPolygon(b) {
+			continue
+		}
+
+		for name, op := range ops {
+			found, err := op([][][]gopostgis.Point{a}, [][][]gopostgis.Point{b})
+			if err != nil {
+				t.Fatal("expected: nil found:", err)
+			}
+			for _, p := range found {
+				if reason := gopostgis.PolygonValidReason(p); reason.Code != gopostgis.ValidityOK {
+					t.Error(name, "of", a, b, "expected: valid found:", reason)
+				}
+			}
+		}
+	}
+}
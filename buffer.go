@@ -0,0 +1,342 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+)
+
+// Planar buffers, same as postgis ST_Buffer on geometry. Buffers are built
+// from the pieces swept by every segment, join and end cap, merged by the
+// overlay. Results are 2D polygons in the SRID of the input, with clockwise
+// exterior rings and counter clockwise interior rings like ST_Buffer.
+
+// BufferEndCap is the shape of the ends of a buffered line.
+type BufferEndCap int
+
+const (
+	BufferCapRound BufferEndCap = iota
+	BufferCapFlat
+	BufferCapSquare
+)
+
+// BufferJoin is the shape of the outer corners of a buffered line.
+type BufferJoin int
+
+const (
+	BufferJoinRound BufferJoin = iota
+	BufferJoinMitre
+	BufferJoinBevel
+)
+
+// BufferOptions mirrors the style parameters of ST_Buffer, the zero value
+// is the postgis default 'quad_segs=8 endcap=round join=round mitre_limit=5'.
+type BufferOptions struct {
+	// Number of segments approximating a quarter circle.
+	QuadSegs   int
+	EndCap     BufferEndCap
+	Join       BufferJoin
+	MitreLimit float64
+}
+
+func (o BufferOptions) withDefaults() (BufferOptions, error) {
+	if o.QuadSegs == 0 {
+		o.QuadSegs = 8
+	}
+	if o.MitreLimit == 0 {
+		o.MitreLimit = 5
+	}
+
+	if o.QuadSegs < 1 {
+		return o, fmt.Errorf("buffer quad segs must be positive. got: %d", o.QuadSegs)
+	}
+	if o.MitreLimit < 1 {
+		return o, fmt.Errorf("buffer mitre limit must be at least 1. got: %g", o.MitreLimit)
+	}
+	if o.EndCap < BufferCapRound || o.EndCap > BufferCapSquare {
+		return o, fmt.Errorf("invalid buffer end cap. got: %d", o.EndCap)
+	}
+	if o.Join < BufferJoinRound || o.Join > BufferJoinBevel {
+		return o, fmt.Errorf("invalid buffer join. got: %d", o.Join)
+	}
+
+	return o, nil
+}
+
+// BufferPoint returns the polygon covering every point within distance of
+// p. It is nil for non positive distances and flat end caps, like the
+// empty polygon of ST_Buffer.
+func BufferPoint[T PointType, P interface {
+	*T
+	setData(pointData)
+}](p T, distance float64, o BufferOptions) ([][]T, error) {
+	o, err := o.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	d := p.data()
+	if err := checkBufferInput(d, distance); err != nil {
+		return nil, err
+	}
+
+	ring := pointBuffer(d.coords[0], d.coords[1], distance, o)
+	if ring == nil {
+		return nil, nil
+	}
+
+	return toPolygon[T, P](ring, nil, d.srid), nil
+}
+
+// BufferLine returns the polygons covering every point within distance of
+// line. The result is nil for non positive distances.
+func BufferLine[T PointType, P interface {
+	*T
+	setData(pointData)
+}](line []T, distance float64, o BufferOptions) ([][][]T, error) {
+	o, err := o.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, fmt.Errorf("buffer of an empty line")
+	}
+
+	for _, p := range line {
+		if err := checkBufferInput(p.data(), distance); err != nil {
+			return nil, err
+		}
+	}
+
+	srid := line[0].data().srid
+	if distance <= 0 {
+		return nil, nil
+	}
+
+	xy := dedupPoints(pointsXY(line))
+	if len(xy) == 1 {
+		ring := pointBuffer(xy[0][0], xy[0][1], distance, o)
+		if ring == nil {
+			return nil, nil
+		}
+		return [][][]T{toPolygon[T, P](ring, nil, srid)}, nil
+	}
+
+	pieces := lineBufferPieces(xy, false, distance, o, 0)
+
+	return toMultiPolygon[T, P](overlay(pieces, overlayUnion), srid), nil
+}
+
+// BufferPolygon returns the polygons covering every point within distance
+// of polygon, a negative distance shrinks the polygon and may split it.
+func BufferPolygon[T PointType, P interface {
+	*T
+	setData(pointData)
+}](polygon [][]T, distance float64, o BufferOptions) ([][][]T, error) {
+	o, err := o.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(polygon) == 0 || len(polygon[0]) == 0 {
+		return nil, fmt.Errorf("buffer of an empty polygon")
+	}
+
+	for _, r := range polygon {
+		for _, p := range r {
+			if err := checkBufferInput(p.data(), distance); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	srid := polygon[0][0].data().srid
+	pieces := []overlayPiece{newOverlayPiece(polygonXY(polygon), 0)}
+
+	op, group := overlayUnion, 0
+	if distance < 0 {
+		op, group = overlayDifference, 1
+	}
+
+	if distance != 0 {
+		for _, r := range pieces[0].rings {
+			closed := append(append([][2]float64{}, r...), r[0])
+			pieces = append(pieces, lineBufferPieces(closed, true, math.Abs(distance), o, group)...)
+		}
+	}
+
+	return toMultiPolygon[T, P](overlay(pieces, op), srid), nil
+}
+
+func checkBufferInput(d pointData, distance float64) error {
+	if !d.valid || d.empty {
+		return fmt.Errorf("buffer of NULL or EMPTY point")
+	}
+
+	if _, err := checkFinite(append(d.values(), distance)...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Drops consecutive repeated points.
+func dedupPoints(xy [][2]float64) [][2]float64 {
+	var points [][2]float64
+	for _, c := range xy {
+		if len(points) == 0 || points[len(points)-1] != c {
+			points = append(points, c)
+		}
+	}
+
+	return points
+}
+
+// Closed ring of the buffer of a point, same points as GEOS.
+func pointBuffer(x, y, distance float64, o BufferOptions) [][2]float64 {
+	if distance <= 0 {
+		return nil
+	}
+
+	switch o.EndCap {
+	case BufferCapFlat:
+		return nil
+
+	case BufferCapSquare:
+		return [][2]float64{
+			{x + distance, y + distance},
+			{x + distance, y - distance},
+			{x - distance, y - distance},
+			{x - distance, y + distance},
+			{x + distance, y + distance},
+		}
+	}
+
+	ring := fillet([2]float64{x, y}, 0, -2*math.Pi, distance, o.QuadSegs)
+
+	return append(ring[:len(ring)-1], ring[0])
+}
+
+// Points of the arc around c of radius r from angle start sweeping by
+// sweep radians, both end points included.
+func fillet(c [2]float64, start, sweep, r float64, quadSegs int) [][2]float64 {
+	quantum := math.Pi / 2 / float64(quadSegs)
+	n := int(math.Abs(sweep)/quantum + 0.5)
+	if n < 1 {
+		n = 1
+	}
+
+	points := make([][2]float64, 0, n+1)
+	for i := 0; i <= n; i++ {
+		a := start + sweep*float64(i)/float64(n)
+		points = append(points, [2]float64{c[0] + r*math.Cos(a), c[1] + r*math.Sin(a)})
+	}
+
+	return points
+}
+
+// Pieces swept by buffering the line xy by distance, closed lines get a
+// join at their closing point instead of end caps.
+func lineBufferPieces(xy [][2]float64, closed bool, distance float64, o BufferOptions, group int) []overlayPiece {
+	n := len(xy)
+	dirs := make([][2]float64, n-1)
+	for i := range dirs {
+		dx, dy := xy[i+1][0]-xy[i][0], xy[i+1][1]-xy[i][1]
+		l := math.Hypot(dx, dy)
+		dirs[i] = [2]float64{dx / l, dy / l}
+	}
+
+	piece := func(ring ...[2]float64) overlayPiece {
+		return newOverlayPiece([][][2]float64{ring}, group)
+	}
+	offset := func(c, u [2]float64, side float64) [2]float64 {
+		return [2]float64{c[0] - side*distance*u[1], c[1] + side*distance*u[0]}
+	}
+
+	var pieces []overlayPiece
+	for i, u := range dirs {
+		a, b := xy[i], xy[i+1]
+		pieces = append(pieces, piece(offset(a, u, 1), offset(a, u, -1), offset(b, u, -1), offset(b, u, 1)))
+	}
+
+	for i := 1; i < n; i++ {
+		if i == n-1 && !closed {
+			break
+		}
+		u1, u2 := dirs[i-1], dirs[(i)%(n-1)]
+		if p, ok := joinPiece(xy[i], u1, u2, distance, o); ok {
+			pieces = append(pieces, newOverlayPiece([][][2]float64{p}, group))
+		}
+	}
+
+	if !closed {
+		for _, end := range [2]struct {
+			c [2]float64
+			u [2]float64
+		}{
+			{xy[0], [2]float64{-dirs[0][0], -dirs[0][1]}},
+			{xy[n-1], dirs[n-2]},
+		} {
+			c, u := end.c, end.u
+			switch o.EndCap {
+			case BufferCapRound:
+				start := math.Atan2(-u[0], u[1]) // right of the outward direction
+				pieces = append(pieces, piece(append([][2]float64{c}, fillet(c, start, math.Pi, distance, o.QuadSegs)...)...))
+
+			case BufferCapSquare:
+				e := [2]float64{c[0] + distance*u[0], c[1] + distance*u[1]}
+				pieces = append(pieces, piece(offset(c, u, 1), offset(c, u, -1), offset(e, u, -1), offset(e, u, 1)))
+			}
+		}
+	}
+
+	return pieces
+}
+
+// Piece filling the outer corner at c between directions u1 and u2.
+func joinPiece(c, u1, u2 [2]float64, distance float64, o BufferOptions) ([][2]float64, bool) {
+	cross := u1[0]*u2[1] - u1[1]*u2[0]
+	dot := u1[0]*u2[0] + u1[1]*u2[1]
+
+	// straight, or folding back onto itself
+	if math.Abs(cross) < 1e-12 {
+		if dot > 0 || o.Join != BufferJoinRound {
+			return nil, false
+		}
+		start := math.Atan2(-u1[0], u1[1])
+		return append([][2]float64{c}, fillet(c, start, math.Pi, distance, o.QuadSegs)...), true
+	}
+
+	// the outer side is right of a left turn
+	side := 1.0
+	if cross > 0 {
+		side = -1
+	}
+	n1 := [2]float64{-side * u1[1], side * u1[0]}
+	n2 := [2]float64{-side * u2[1], side * u2[0]}
+	a := [2]float64{c[0] + distance*n1[0], c[1] + distance*n1[1]}
+	b := [2]float64{c[0] + distance*n2[0], c[1] + distance*n2[1]}
+
+	switch o.Join {
+	case BufferJoinRound:
+		start := math.Atan2(n1[1], n1[0])
+		sweep := math.Atan2(n1[0]*n2[1]-n1[1]*n2[0], n1[0]*n2[0]+n1[1]*n2[1])
+		return append([][2]float64{c}, fillet(c, start, sweep, distance, o.QuadSegs)...), true
+
+	case BufferJoinMitre:
+		cosine := n1[0]*n2[0] + n1[1]*n2[1]
+		m := [2]float64{c[0] + distance*(n1[0]+n2[0])/(1+cosine), c[1] + distance*(n1[1]+n2[1])/(1+cosine)}
+		ratio := math.Sqrt(2 / (1 + cosine))
+		if ratio <= o.MitreLimit {
+			return [][2]float64{c, a, m, b}, true
+		}
+
+		// bevel the mitre where it reaches the limit
+		t := (o.MitreLimit - math.Sqrt((1+cosine)/2)) / (ratio - math.Sqrt((1+cosine)/2))
+		p1 := [2]float64{a[0] + t*(m[0]-a[0]), a[1] + t*(m[1]-a[1])}
+		p2 := [2]float64{b[0] + t*(m[0]-b[0]), b[1] + t*(m[1]-b[1])}
+		return [][2]float64{c, a, p1, p2, b}, true
+	}
+
+	return [][2]float64{c, a, b}, true
+}
@@ -0,0 +1,311 @@
+package gopostgis_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// Signed area of a closed ring, negative when clockwise.
+func ringArea(ring []gopostgis.Point) float64 {
+	var a float64
+	for i := 1; i < len(ring); i++ {
+		a += ring[i-1].X*ring[i].Y - ring[i].X*ring[i-1].Y
+	}
+	return a / 2
+}
+
+// Area of polygons with clockwise shells and counter clockwise holes.
+func polygonsArea(polygons [][][]gopostgis.Point) float64 {
+	var a float64
+	for _, p := range polygons {
+		for _, r := range p {
+			a -= ringArea(r)
+		}
+	}
+	return a
+}
+
+func hasVertex(ring []gopostgis.Point, x, y float64) bool {
+	for _, p := range ring {
+		if math.Abs(p.X-x) < 1e-9 && math.Abs(p.Y-y) < 1e-9 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBufferPoint(t *testing.T) {
+	t.Run("circle", func(t *testing.T) {
+		found, err := gopostgis.BufferPoint(gopostgis.Point{X: 0, Y: 0, Valid: true}, 1, gopostgis.BufferOptions{QuadSegs: 2})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+
+		h := math.Sqrt2 / 2
+		expected := xyLine(1, 0, h, -h, 0, -1, -h, -h, -1, 0, -h, h, 0, 1, h, h, 1, 0)
+		if len(found) != 1 || len(found[0]) != len(expected) {
+			t.Fatal("expected:", expected, "found:", found)
+		}
+		for i := range expected {
+			if math.Abs(found[0][i].X-expected[i].X) > 1e-12 || math.Abs(found[0][i].Y-expected[i].Y) > 1e-12 {
+				t.Error("expected:", expected, "found:", found)
+				break
+			}
+		}
+	})
+
+	t.Run("square cap", func(t *testing.T) {
+		found, err := gopostgis.BufferPoint(
+			gopostgis.PointS{SRID: 3857, X: 5, Y: 5, Valid: true}, 2,
+			gopostgis.BufferOptions{EndCap: gopostgis.BufferCapSquare},
+		)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		expected := [][]gopostgis.PointS{{
+			{SRID: 3857, X: 7, Y: 7, Valid: true},
+			{SRID: 3857, X: 7, Y: 3, Valid: true},
+			{SRID: 3857, X: 3, Y: 3, Valid: true},
+			{SRID: 3857, X: 3, Y: 7, Valid: true},
+			{SRID: 3857, X: 7, Y: 7, Valid: true},
+		}}
+		if len(found) != 1 || len(found[0]) != 5 {
+			t.Fatal("expected:", expected, "found:", found)
+		}
+		for i := range expected[0] {
+			if found[0][i] != expected[0][i] {
+				t.Error("expected:", expected, "found:", found)
+				break
+			}
+		}
+	})
+
+	t.Run("empty results", func(t *testing.T) {
+		p := gopostgis.Point{X: 1, Y: 1, Valid: true}
+		if found, err := gopostgis.BufferPoint(p, 0, gopostgis.BufferOptions{}); found != nil || err != nil {
+			t.Error("expected:", nil, "found:", found, err)
+		}
+		if found, err := gopostgis.BufferPoint(p, 1, gopostgis.BufferOptions{EndCap: gopostgis.BufferCapFlat}); found != nil || err != nil {
+			t.Error("expected:", nil, "found:", found, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.BufferPoint(gopostgis.Point{}, 1, gopostgis.BufferOptions{}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.BufferPoint(gopostgis.Point{Valid: true}, 1, gopostgis.BufferOptions{QuadSegs: -1}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.BufferPoint(gopostgis.Point{Valid: true}, math.Inf(1), gopostgis.BufferOptions{}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestBufferLine(t *testing.T) {
+	t.Run("flat cap", func(t *testing.T) {
+		found, err := gopostgis.BufferLine(xyLine(0, 0, 10, 0), 1, gopostgis.BufferOptions{EndCap: gopostgis.BufferCapFlat})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 1 || len(found[0][0]) != 5 {
+			t.Fatal("expected: rectangle found:", found)
+		}
+		for _, c := range [][2]float64{{0, -1}, {10, -1}, {10, 1}, {0, 1}} {
+			if !hasVertex(found[0][0], c[0], c[1]) {
+				t.Error("expected:", c, "found:", found)
+			}
+		}
+		if a := ringArea(found[0][0]); a != -20 {
+			t.Error("expected:", -20, "found:", a)
+		}
+	})
+
+	t.Run("round cap", func(t *testing.T) {
+		found, err := gopostgis.BufferLine(xyLine(0, 0, 10, 0), 1, gopostgis.BufferOptions{})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		// two halves of a 32 sided polygon
+		expected := 20 + 16*math.Sin(math.Pi/16)
+		if a := polygonsArea(found); math.Abs(a-expected) > 1e-9 {
+			t.Error("expected:", expected, "found:", a)
+		}
+	})
+
+	t.Run("joins", func(t *testing.T) {
+		line := xyLine(0, 0, 10, 0, 10, 10)
+		cases := []struct {
+			join   gopostgis.BufferJoin
+			limit  float64
+			area   float64
+			corner bool
+		}{
+			{gopostgis.BufferJoinMitre, 0, 40, true},
+			{gopostgis.BufferJoinBevel, 0, 39.5, false},
+			{gopostgis.BufferJoinMitre, 1, 40 - (3 - 2*math.Sqrt2), false},
+			{gopostgis.BufferJoinRound, 0, 39 + 4*math.Sin(math.Pi/16), false},
+		}
+
+		for _, c := range cases {
+			found, err := gopostgis.BufferLine(line, 1, gopostgis.BufferOptions{
+				EndCap:     gopostgis.BufferCapFlat,
+				Join:       c.join,
+				MitreLimit: c.limit,
+			})
+			if err != nil {
+				t.Fatal("expected: nil found:", err)
+			}
+			if len(found) != 1 || len(found[0]) != 1 {
+				t.Fatal("expected: one polygon found:", found)
+			}
+			if a := polygonsArea(found); math.Abs(a-c.area) > 1e-9 {
+				t.Error("expected:", c.area, "found:", a)
+			}
+			if corner := hasVertex(found[0][0], 11, -1); corner != c.corner {
+				t.Error("expected:", c.corner, "found:", corner)
+			}
+		}
+	})
+
+	t.Run("loop makes a hole", func(t *testing.T) {
+		found, err := gopostgis.BufferLine(xyLine(0, 0, 10, 0, 10, 10, 0, 10, 0, 0), 1, gopostgis.BufferOptions{})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 2 {
+			t.Fatal("expected: polygon with a hole found:", found)
+		}
+		if a := ringArea(found[0][0]); a >= 0 {
+			t.Error("expected: clockwise shell found:", a)
+		}
+		if a := ringArea(found[0][1]); a != 64 {
+			t.Error("expected:", 64, "found:", a)
+		}
+	})
+
+	t.Run("single point", func(t *testing.T) {
+		found, err := gopostgis.BufferLine(xyLine(1, 1, 1, 1), 1, gopostgis.BufferOptions{EndCap: gopostgis.BufferCapSquare})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || ringArea(found[0][0]) != -4 {
+			t.Error("expected: square found:", found)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.BufferLine([]gopostgis.Point{}, 1, gopostgis.BufferOptions{}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.BufferLine(xyLine(0, 0, 1, 1), 1, gopostgis.BufferOptions{Join: 5}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestBufferPolygon(t *testing.T) {
+	square := [][]gopostgis.Point{xyLine(0, 0, 10, 0, 10, 10, 0, 10, 0, 0)}
+
+	t.Run("grow", func(t *testing.T) {
+		found, err := gopostgis.BufferPolygon(square, 1, gopostgis.BufferOptions{Join: gopostgis.BufferJoinMitre})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 1 || polygonsArea(found) != 144 {
+			t.Error("expected: 12x12 square found:", found)
+		}
+	})
+
+	t.Run("shrink", func(t *testing.T) {
+		found, err := gopostgis.BufferPolygon(square, -1, gopostgis.BufferOptions{})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 1 || polygonsArea(found) != 64 {
+			t.Error("expected: 8x8 square found:", found)
+		}
+		for _, c := range [][2]float64{{1, 1}, {9, 1}, {9, 9}, {1, 9}} {
+			if !hasVertex(found[0][0], c[0], c[1]) {
+				t.Error("expected:", c, "found:", found)
+			}
+		}
+	})
+
+	t.Run("shrink splits", func(t *testing.T) {
+		// two squares joined by a thin corridor
+		dumbbell := [][]gopostgis.Point{xyLine(0, 0, 4, 0, 4, 1.5, 6, 1.5, 6, 0, 10, 0, 10, 4, 6, 4, 6, 2.5, 4, 2.5, 4, 4, 0, 4, 0, 0)}
+		found, err := gopostgis.BufferPolygon(dumbbell, -1, gopostgis.BufferOptions{})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 2 {
+			t.Error("expected: 2 polygons found:", found)
+		}
+	})
+
+	t.Run("grow fills hole", func(t *testing.T) {
+		donut := [][]gopostgis.Point{square[0], xyLine(4, 4, 4, 6, 6, 6, 6, 4, 4, 4)}
+		found, err := gopostgis.BufferPolygon(donut, 1.5, gopostgis.BufferOptions{Join: gopostgis.BufferJoinMitre})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 1 || polygonsArea(found) != 169 {
+			t.Error("expected: 13x13 square found:", found)
+		}
+	})
+
+	t.Run("zero distance", func(t *testing.T) {
+		found, err := gopostgis.BufferPolygon(square, 0, gopostgis.BufferOptions{})
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || polygonsArea(found) != 100 {
+			t.Error("expected:", square, "found:", found)
+		}
+	})
+}
+
+func TestBufferValidity(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		// integer vertices make lines revisit points and overlap themselves
+		line := make([]gopostgis.Point, 3+r.Intn(5))
+		for j := range line {
+			line[j] = gopostgis.Point{X: float64(r.Intn(5)), Y: float64(r.Intn(5)), Valid: true}
+		}
+		o := gopostgis.BufferOptions{
+			QuadSegs: 1 + r.Intn(8),
+			EndCap:   gopostgis.BufferEndCap(r.Intn(3)),
+			Join:     gopostgis.BufferJoin(r.Intn(3)),
+		}
+
+		found, err := gopostgis.BufferLine(line, 1, o)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		for _, p := range found {
+			if reason := gopostgis.PolygonValidReason(p); reason.Code != gopostgis.ValidityOK {
+				t.Error("buffer of", line, o, "expected: valid found:", reason)
+			}
+		}
+
+		polygon := randomStar(r, 0, 0)
+		if !gopostgis.IsValidPolygon(polygon) {
+			continue
+		}
+		found, err = gopostgis.BufferPolygon(polygon, 2*r.Float64()-1, o)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		for _, p := range found {
+			if reason := gopostgis.PolygonValidReason(p); reason.Code != gopostgis.ValidityOK {
+				t.Error("buffer of", polygon, o, "expected: valid found:", reason)
+			}
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package gopostgis
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsonNullString = "null"
+	jsonNullValue  = []byte(jsonNullString)
+)
+
+// ErrNonFiniteCoordinate is returned when a coordinate is NaN or Inf.
+// A point having all of its coordinates NaN is not an error, it is the
+// EWKB convention for an empty point and is written as POINT EMPTY.
+var ErrNonFiniteCoordinate = errors.New("non-finite coordinate")
+
+// Layout of a point datatype. It describes everything the shared codec
+// needs to know about a point type - presence of SRID, names of the
+// coordinates (also used as json keys), the WKT tags and the EWKB
+// dimension flags.
+type pointLayout struct {
+	srid  bool
+	names []string
+	tag   string
+	empty string
+	flags uint32
+}
+
+// Number of coordinates.
+func (l pointLayout) dims() int {
+	return len(l.names)
+}
+
+var (
+	layoutXY = pointLayout{
+		names: []string{"X", "Y"},
+		tag:   "POINT",
+		empty: "POINT EMPTY",
+	}
+
+	// 3 coordinates without a tag are treated as Z by postgis,
+	// so only M needs to be explicit.
+	layoutXYZ = pointLayout{
+		names: []string{"X", "Y", "Z"},
+		tag:   "POINT",
+		empty: "POINT Z EMPTY",
+		flags: ewkbZFlag,
+	}
+	layoutXYM = pointLayout{
+		names: []string{"X", "Y", "M"},
+		tag:   "POINT M",
+		empty: "POINT M EMPTY",
+		flags: ewkbMFlag,
+	}
+	layoutXYZM = pointLayout{
+		names: []string{"X", "Y", "Z", "M"},
+		tag:   "POINT",
+		empty: "POINT ZM EMPTY",
+		flags: ewkbZFlag | ewkbMFlag,
+	}
+)
+
+// withSRID returns a copy of l having SRID.
+func (l pointLayout) withSRID() pointLayout {
+	l.srid = true
+	return l
+}
+
+// PointType is the constraint of the geometry operations over point
+// slices, where a []T is a line or a ring and a [][]T is a polygon, the
+// exterior ring followed by the interior rings.
+type PointType interface {
+	Point | PointS | PointZ | PointZS | PointM | PointMS | PointZM | PointZMS
+	data() pointData
+}
+
+// Dimension independent representation of a point datatype.
+// Every point type converts to and from this to share the
+// implementation of Scan, Value and json marshal/unmarshal.
+type pointData struct {
+	layout pointLayout
+	srid   uint32
+	coords [4]float64
+	empty  bool
+	valid  bool
+}
+
+// Used coordinates of d.
+func (d *pointData) values() []float64 {
+	return d.coords[:d.layout.dims()]
+}
+
+// Scans src according to layout l.
+func scanPoint(src any, l pointLayout) (pointData, error) {
+	d := pointData{layout: l}
+
+	switch v := src.(type) {
+	case nil:
+		return d, nil
+
+	case []byte:
+		var c hexCursor
+		if e := c.init(v); e != nil {
+			return d, e
+		}
+		srid, empty, e := readPoint(&c, l.srid, d.values())
+		if e != nil {
+			return d, e
+		}
+		d.srid = srid
+		d.empty = empty
+		d.valid = true
+		return d, nil
+
+	default:
+		return d, fmt.Errorf("driver datatype not supported")
+	}
+}
+
+// Reads SRID (if hasSRID) and coordinates of a point into values.
+// Reports empty for NaN points and empty collections.
+func readPoint(c *hexCursor, hasSRID bool, values []float64) (uint32, bool, error) {
+	var srid uint32
+	if hasSRID {
+		s, e := c.readUint32()
+		if e != nil {
+			return 0, false, e
+		}
+		srid = s
+	}
+
+	switch baseType(c.dType) {
+	case WKBPoint:
+		if e := c.readDoubles(values); e != nil {
+			return 0, false, e
+		}
+		for _, v := range values {
+			if !math.IsNaN(v) {
+				return srid, false, nil
+			}
+		}
+		for i := range values {
+			values[i] = 0
+		}
+		return srid, true, nil
+
+	case WKBMultiPoint, WKBMultiLineString, WKBMultiPolygon, WKBGeometryCollection:
+		n, e := c.readUint32()
+		if e != nil {
+			return 0, false, e
+		}
+		if n == 0 {
+			return srid, true, nil
+		}
+	}
+
+	return 0, false, fmt.Errorf("geometry type not supported. got: %v", c.dType)
+}
+
+// Checks that every coordinate is finite. Reports empty if all of
+// the coordinates are NaN.
+func checkFinite(coords ...float64) (bool, error) {
+	nan := 0
+	for _, c := range coords {
+		if math.IsNaN(c) {
+			nan++
+		} else if math.IsInf(c, 0) {
+			return false, fmt.Errorf("%w: %v", ErrNonFiniteCoordinate, coords)
+		}
+	}
+
+	if nan == len(coords) {
+		return true, nil
+	}
+
+	if nan > 0 {
+		return false, fmt.Errorf("%w: %v", ErrNonFiniteCoordinate, coords)
+	}
+
+	return false, nil
+}
+
+// Prefix of EWKT, i.e. SRID=4326; or nothing.
+func (d pointData) ewktPrefix() string {
+	if !d.layout.srid {
+		return ""
+	}
+
+	return "SRID=" + strconv.FormatUint(uint64(d.srid), 10) + ";"
+}
+
+// Checks d before writing it to the database. Reports whether d
+// is empty, either explicitly or by the NaN convention.
+func (d pointData) check() (bool, error) {
+	empty, e := checkFinite(d.values()...)
+	if d.empty || empty {
+		return true, nil
+	}
+
+	if e != nil {
+		return false, e
+	}
+
+	if d.layout.srid {
+		if e := ValidateSRID(d.srid, d.coords[0], d.coords[1]); e != nil {
+			return false, e
+		}
+	}
+
+	return false, nil
+}
+
+// Implements driver.Valuer for every point type.
+func (d pointData) value() (driver.Value, error) {
+	if !d.valid {
+		return nil, nil
+	}
+
+	d = d.withPrecision()
+	empty, e := d.check()
+	if e != nil {
+		return nil, e
+	}
+
+	if empty {
+		return d.ewktPrefix() + d.layout.empty, nil
+	}
+
+	b := make([]byte, 0, 64)
+	b = append(b, d.ewktPrefix()...)
+	b = append(b, d.layout.tag...)
+	b = append(b, '(')
+	for i, c := range d.values() {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = strconv.AppendFloat(b, c, 'g', -1, 64)
+	}
+	b = append(b, ')')
+
+	return string(b), nil
+}
+
+// Implements json.Marshaler for every point type.
+func (d pointData) marshalJSON() ([]byte, error) {
+	if !d.valid {
+		return jsonNullValue, nil
+	}
+
+	empty, e := checkFinite(d.values()...)
+	if e != nil && !d.empty {
+		return nil, e
+	}
+
+	b := make([]byte, 0, 64)
+	b = append(b, '{')
+	if d.layout.srid {
+		b = append(b, `"SRID":`...)
+		b = strconv.AppendUint(b, uint64(d.srid), 10)
+		b = append(b, ',')
+	}
+
+	if d.empty || empty {
+		b = append(b, `"Empty":true}`...)
+		return b, nil
+	}
+
+	for i, c := range d.values() {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, '"')
+		b = append(b, d.layout.names[i]...)
+		b = append(b, `":`...)
+		b = appendJSONFloat(b, c)
+	}
+	b = append(b, '}')
+
+	return b, nil
+}
+
+// Appends f formatted the same way encoding/json does.
+func appendJSONFloat(b []byte, f float64) []byte {
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+
+	return b
+}
+
+// Implements json.Unmarshaler for every point type.
+func unmarshalPointJSON(b []byte, l pointLayout) (pointData, error) {
+	d := pointData{layout: l}
+	if string(b) == jsonNullString {
+		return d, nil
+	}
+
+	// raw values, so that keys not belonging to the layout are ignored
+	var tp struct {
+		SRID  json.RawMessage
+		X     json.RawMessage
+		Y     json.RawMessage
+		Z     json.RawMessage
+		M     json.RawMessage
+		Empty bool
+	}
+
+	if err := json.Unmarshal(b, &tp); err != nil {
+		return d, err
+	}
+
+	if l.srid && tp.SRID != nil {
+		if err := json.Unmarshal(tp.SRID, &d.srid); err != nil {
+			return d, err
+		}
+	}
+
+	for i, name := range l.names {
+		var raw json.RawMessage
+		switch name {
+		case "X":
+			raw = tp.X
+		case "Y":
+			raw = tp.Y
+		case "Z":
+			raw = tp.Z
+		case "M":
+			raw = tp.M
+		}
+		if raw == nil {
+			continue
+		}
+		if err := json.Unmarshal(raw, &d.coords[i]); err != nil {
+			return d, jsonCoordError(err)
+		}
+	}
+
+	d.empty = tp.Empty
+	d.valid = true
+
+	return d, nil
+}
+
+// encoding/json reports numbers overflowing float64 as type errors,
+// converts them into ErrNonFiniteCoordinate.
+func jsonCoordError(e error) error {
+	var te *json.UnmarshalTypeError
+	if errors.As(e, &te) && te.Type != nil && te.Type.Kind() == reflect.Float64 &&
+		strings.HasPrefix(te.Value, "number") {
+		return fmt.Errorf("%w: %s", ErrNonFiniteCoordinate, strings.TrimPrefix(te.Value, "number "))
+	}
+
+	return e
+}
@@ -0,0 +1,257 @@
+package gopostgis
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Signature, flags and header extension length of COPY BINARY format.
+var copyBinaryHeader = []byte("PGCOPY\n\xff\r\n\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+// CopyTextWriter writes rows in the text format of postgresql
+// COPY ... FROM STDIN, which can be streamed to the server by any
+// driver supporting COPY, e.g. pgconn.PgConn.CopyFrom.
+// Instance of this struct should be initialized by [NewCopyTextWriter] function.
+type CopyTextWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// Creates an instance of [CopyTextWriter] writing to w.
+func NewCopyTextWriter(w io.Writer) *CopyTextWriter {
+	return &CopyTextWriter{
+		w: w,
+	}
+}
+
+// WriteRow writes one row of fields. Fields implementing [EWKBMarshaler]
+// are written as hex encoded EWKB, so no precision is lost. Other fields
+// are converted by driver.DefaultParameterConverter and written in the
+// text representation of postgresql.
+func (c *CopyTextWriter) WriteRow(fields ...any) error {
+	c.buf = c.buf[:0]
+
+	for i, f := range fields {
+		if i > 0 {
+			c.buf = append(c.buf, '\t')
+		}
+
+		b, err := appendCopyText(c.buf, f)
+		if err != nil {
+			return fmt.Errorf("field %d: %w", i, err)
+		}
+		c.buf = b
+	}
+
+	c.buf = append(c.buf, '\n')
+
+	_, err := c.w.Write(c.buf)
+
+	return err
+}
+
+func appendCopyText(b []byte, f any) ([]byte, error) {
+	if isNilPointer(f) {
+		return append(b, `\N`...), nil
+	}
+
+	if m, ok := f.(EWKBMarshaler); ok {
+		ewkb, err := m.MarshalEWKB()
+		if err != nil {
+			return nil, err
+		}
+		if ewkb == nil {
+			return append(b, `\N`...), nil
+		}
+		return appendHex(b, ewkb), nil
+	}
+
+	v, err := driver.DefaultParameterConverter.ConvertValue(f)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return append(b, `\N`...), nil
+
+	case int64:
+		return strconv.AppendInt(b, v, 10), nil
+
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return append(b, "NaN"...), nil
+		case math.IsInf(v, 1):
+			return append(b, "Infinity"...), nil
+		case math.IsInf(v, -1):
+			return append(b, "-Infinity"...), nil
+		}
+		return strconv.AppendFloat(b, v, 'g', -1, 64), nil
+
+	case bool:
+		if v {
+			return append(b, 't'), nil
+		}
+		return append(b, 'f'), nil
+
+	case []byte:
+		if v == nil {
+			return append(b, `\N`...), nil
+		}
+		// bytea hex format, backslash escaped for COPY
+		b = append(b, `\\x`...)
+		return appendHex(b, v), nil
+
+	case string:
+		return appendCopyEscaped(b, v), nil
+
+	case time.Time:
+		return v.AppendFormat(b, "2006-01-02 15:04:05.999999999Z07:00"), nil
+	}
+
+	return nil, fmt.Errorf("unsupported field type %T", v)
+}
+
+func appendHex(b []byte, v []byte) []byte {
+	n := len(b)
+	b = append(b, make([]byte, hex.EncodedLen(len(v)))...)
+	hex.Encode(b[n:], v)
+
+	return b
+}
+
+// Escapes the characters having special meaning in COPY text format.
+func appendCopyEscaped(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			b = append(b, `\\`...)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case '\t':
+			b = append(b, `\t`...)
+		default:
+			b = append(b, c)
+		}
+	}
+
+	return b
+}
+
+// CopyBinaryWriter writes rows in the binary format of postgresql
+// COPY ... FROM STDIN WITH (FORMAT binary). Geometry is sent as EWKB,
+// which is what postgis expects in binary protocols.
+// Instance of this struct should be initialized by [NewCopyBinaryWriter] function.
+type CopyBinaryWriter struct {
+	w      io.Writer
+	buf    []byte
+	header bool
+}
+
+// Creates an instance of [CopyBinaryWriter] writing to w.
+func NewCopyBinaryWriter(w io.Writer) *CopyBinaryWriter {
+	return &CopyBinaryWriter{
+		w: w,
+	}
+}
+
+// WriteRow writes one row of fields. Fields implementing [EWKBMarshaler]
+// are written as EWKB, nil fields and nil pointers are written as NULL.
+// Columns of other types must be passed as []byte already encoded in the
+// binary format of the column type, as it depends on the column type.
+func (c *CopyBinaryWriter) WriteRow(fields ...any) error {
+	if len(fields) > math.MaxInt16 {
+		return fmt.Errorf("too many fields. got: %d", len(fields))
+	}
+
+	c.buf = c.buf[:0]
+	if !c.header {
+		c.buf = append(c.buf, copyBinaryHeader...)
+	}
+
+	c.buf = appendInt16BE(c.buf, int16(len(fields)))
+
+	for i, f := range fields {
+		if isNilPointer(f) {
+			f = nil
+		}
+
+		var data []byte
+		switch v := f.(type) {
+		case nil:
+		case EWKBMarshaler:
+			ewkb, err := v.MarshalEWKB()
+			if err != nil {
+				return fmt.Errorf("field %d: %w", i, err)
+			}
+			data = ewkb
+		case []byte:
+			data = v
+		default:
+			return fmt.Errorf("field %d: unsupported field type %T", i, f)
+		}
+
+		if data == nil {
+			c.buf = appendInt32BE(c.buf, -1)
+			continue
+		}
+
+		c.buf = appendInt32BE(c.buf, int32(len(data)))
+		c.buf = append(c.buf, data...)
+	}
+
+	if _, err := c.w.Write(c.buf); err != nil {
+		return err
+	}
+
+	c.header = true
+
+	return nil
+}
+
+// Close writes the trailer of COPY BINARY format. It does not close the
+// underlying writer.
+func (c *CopyBinaryWriter) Close() error {
+	c.buf = c.buf[:0]
+	if !c.header {
+		c.buf = append(c.buf, copyBinaryHeader...)
+	}
+
+	c.buf = appendInt16BE(c.buf, -1)
+
+	if _, err := c.w.Write(c.buf); err != nil {
+		return err
+	}
+
+	c.header = true
+
+	return nil
+}
+
+// Tells whether f is a nil pointer, e.g. a nil *Point, which is NULL.
+func isNilPointer(f any) bool {
+	v := reflect.ValueOf(f)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func appendInt16BE(b []byte, v int16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], uint16(v))
+	return append(b, buf[:]...)
+}
+
+func appendInt32BE(b []byte, v int32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(v))
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,190 @@
+package gopostgis_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// Fake COPY BINARY sink, parses the stream the way postgresql does.
+func readCopyBinary(r io.Reader) ([][][]byte, error) {
+	header := make([]byte, 19)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[:11]) != "PGCOPY\n\xff\r\n\x00" {
+		return nil, errors.New("invalid signature")
+	}
+
+	var rows [][][]byte
+	for {
+		var n int16
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return nil, err
+		}
+		if n == -1 {
+			return rows, nil
+		}
+
+		row := make([][]byte, n)
+		for i := range row {
+			var l int32
+			if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+				return nil, err
+			}
+			if l == -1 {
+				continue
+			}
+			row[i] = make([]byte, l)
+			if _, err := io.ReadFull(r, row[i]); err != nil {
+				return nil, err
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestCopyBinary(t *testing.T) {
+	var buf bytes.Buffer
+	w := gopostgis.NewCopyBinaryWriter(&buf)
+
+	id := []byte{0, 0, 0, 1} // int4 1
+	p := gopostgis.PointZS{SRID: 4326, X: 10, Y: 20, Z: 30, Valid: true}
+	if e := w.WriteRow(id, p, nil); e != nil {
+		t.Fatal(e)
+	}
+	if e := w.WriteRow(id, gopostgis.PointZS{}, gopostgis.Point{Empty: true, Valid: true}); e != nil {
+		t.Fatal(e)
+	}
+	if e := w.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	rows, e := readCopyBinary(&buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(rows) != 2 {
+		t.Fatal("expected: 2 rows found:", len(rows))
+	}
+
+	// SRID=4326;POINT(10 20 30)
+	expected := "01010000A0E6100000000000000000244000000000000034400000000000003E40"
+	if found := strings.ToUpper(hex.EncodeToString(rows[0][1])); found != expected {
+		t.Error("expected:", expected, "found:", found)
+	}
+	if !bytes.Equal(rows[0][0], id) || rows[0][2] != nil {
+		t.Error("unexpected row:", rows[0])
+	}
+
+	if rows[1][1] != nil {
+		t.Error("expected NULL found:", rows[1][1])
+	}
+
+	var empty gopostgis.Point
+	if e := empty.Scan([]byte(hex.EncodeToString(rows[1][2]))); e != nil {
+		t.Error(e)
+	}
+	if !empty.Valid || !empty.Empty {
+		t.Error("expected empty point found:", empty)
+	}
+}
+
+func TestCopyBinaryUnsupported(t *testing.T) {
+	w := gopostgis.NewCopyBinaryWriter(io.Discard)
+	if e := w.WriteRow(1); e == nil {
+		t.Error("int should not be accepted in binary format")
+	}
+
+	p := gopostgis.Point{X: math.Inf(1), Y: 0, Valid: true}
+	if e := w.WriteRow(p); !errors.Is(e, gopostgis.ErrNonFiniteCoordinate) {
+		t.Error("expected:", gopostgis.ErrNonFiniteCoordinate, "found:", e)
+	}
+}
+
+func TestCopyNilPointer(t *testing.T) {
+	var p *gopostgis.PointS
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if e := gopostgis.NewCopyTextWriter(&buf).WriteRow(1, p); e != nil {
+			t.Fatal(e)
+		}
+		if expected := "1\t\\N\n"; buf.String() != expected {
+			t.Error("expected:", expected, "found:", buf.String())
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := gopostgis.NewCopyBinaryWriter(&buf)
+		if e := w.WriteRow([]byte{0, 0, 0, 1}, p); e != nil {
+			t.Fatal(e)
+		}
+		if e := w.Close(); e != nil {
+			t.Fatal(e)
+		}
+
+		rows, e := readCopyBinary(&buf)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(rows) != 1 || len(rows[0]) != 2 || rows[0][1] != nil {
+			t.Error("expected: NULL found:", rows)
+		}
+	})
+}
+
+func TestCopyText(t *testing.T) {
+	var buf bytes.Buffer
+	w := gopostgis.NewCopyTextWriter(&buf)
+
+	p := gopostgis.PointS{SRID: 4326, X: 10, Y: 20, Valid: true}
+	if e := w.WriteRow(1, "a\tb\\c\n", p, nil, gopostgis.Point{}, []byte{0xde, 0xad}, true, 1.5); e != nil {
+		t.Fatal(e)
+	}
+
+	expected := "1\ta\\tb\\\\c\\n\t0101000020e610000000000000000024400000000000003440\t\\N\t\\N\t\\\\xdead\tt\t1.5\n"
+	if buf.String() != expected {
+		t.Error("expected:", expected, "found:", buf.String())
+	}
+
+	var scanned gopostgis.PointS
+	field := strings.Split(buf.String(), "\t")[2]
+	if e := scanned.Scan([]byte(field)); e != nil {
+		t.Error(e)
+	}
+	if scanned != p {
+		t.Error("expected:", p, "found:", scanned)
+	}
+}
+
+func ExampleCopyTextWriter() {
+	// the writer is normally streamed to the server by the driver,
+	// e.g. pgconn.PgConn.CopyFrom(ctx, r, "COPY test_table (id, location) FROM STDIN")
+	w := gopostgis.NewCopyTextWriter(os.Stdout)
+
+	points := []gopostgis.Point{
+		{X: 10, Y: 20, Valid: true},
+		{Valid: false},
+	}
+
+	for i, p := range points {
+		if e := w.WriteRow(i+1, p); e != nil {
+			fmt.Println(e)
+		}
+	}
+
+	// Output:
+	// 1	010100000000000000000024400000000000003440
+	// 2	\N
+}
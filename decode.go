@@ -4,13 +4,45 @@
 package gopostgis
 
 import (
-	"bytes"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"math"
 )
 
+// Flags of EWKB geometry type
+const (
+	ewkbZFlag    uint32 = 0x80000000
+	ewkbMFlag    uint32 = 0x40000000
+	ewkbSRIDFlag uint32 = 0x20000000
+)
+
+// Geometry types of WKB specification, as reported by
+// [HexEWKBDecoder.Type] once the flags are stripped.
+const (
+	WKBPoint              uint32 = 1
+	WKBLineString         uint32 = 2
+	WKBPolygon            uint32 = 3
+	WKBMultiPoint         uint32 = 4
+	WKBMultiLineString    uint32 = 5
+	WKBMultiPolygon       uint32 = 6
+	WKBGeometryCollection uint32 = 7
+	WKBCircularString     uint32 = 8
+	WKBCompoundCurve      uint32 = 9
+	WKBCurvePolygon       uint32 = 10
+	WKBMultiCurve         uint32 = 11
+	WKBMultiSurface       uint32 = 12
+	WKBPolyhedralSurface  uint32 = 15
+	WKBTIN                uint32 = 16
+	WKBTriangle           uint32 = 17
+)
+
+// Strips EWKB flags and ISO WKB dimension offsets from geometry type t.
+func baseType(t uint32) uint32 {
+	return (t &^ (ewkbZFlag | ewkbMFlag | ewkbSRIDFlag)) % 1000
+}
+
 // Decoder for hex encoded EWKB data.
 // reference - https://github.com/postgis/postgis/blob/master/doc/bnf-wkb.txt
 // It does not implement any specific postgis datatype, instead it is
@@ -38,86 +70,229 @@ type HexEWKBDecoder interface {
 
 // Creates an instance of [HexEWKBDecoder].
 func NewHexEWKBDecoder(data []byte) (HexEWKBDecoder, error) {
-	hexData, err := hex.DecodeString(string(data))
-	if err != nil {
+	d := hexEWKBDecoder{}
+	if err := d.init(data); err != nil {
 		return nil, err
 	}
 
-	d := hexEWKBDecoder{
-		buf: bytes.NewBuffer(hexData),
+	return &d, nil
+}
+
+// Cursor over hex encoded EWKB data. It decodes the hex digits in place
+// while reading, so neither the decoded data nor the read values are
+// allocated. It is used by value to scan the point types.
+// In raw mode data is binary WKB and is read as is.
+type hexCursor struct {
+	data   []byte
+	pos    int
+	raw    bool
+	little bool
+	order  binary.ByteOrder
+	dType  uint32
+}
+
+// Reads byteorder and datatype of hex encoded data.
+func (c *hexCursor) init(data []byte) error {
+	if len(data)%2 != 0 {
+		return hex.ErrLength
 	}
 
-	var order byte
-	var dType uint32
+	c.raw = false
 
-	if err := binary.Read(d.buf, binary.LittleEndian, &order); err != nil {
-		return nil, err
+	return c.start(data)
+}
+
+// Reads byteorder and datatype of binary data.
+func (c *hexCursor) initRaw(data []byte) error {
+	c.raw = true
+
+	return c.start(data)
+}
+
+func (c *hexCursor) start(data []byte) error {
+	c.data = data
+	c.pos = 0
+
+	order, err := c.readByte()
+	if err != nil {
+		return err
 	}
 
 	switch order {
 	case 0x01:
-		d.byteOrder = binary.LittleEndian
+		c.little = true
+		c.order = binary.LittleEndian
 
 	case 0x00:
-		d.byteOrder = binary.BigEndian
+		c.little = false
+		c.order = binary.BigEndian
 
 	default:
-		return nil, fmt.Errorf("unknown byteorder. got: %v", order)
+		return fmt.Errorf("unknown byteorder. got: %v", order)
 	}
 
-	if err := binary.Read(d.buf, d.byteOrder, &dType); err != nil {
-		return nil, err
+	dType, err := c.readUint32()
+	if err != nil {
+		return err
 	}
 
-	d.dType = dType
+	c.dType = dType
 
-	return &d, nil
+	return nil
 }
 
-type hexEWKBDecoder struct {
-	buf       io.Reader
-	byteOrder binary.ByteOrder
+// Converts a hex digit into its value.
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
 
-	dType uint32
+	return 0, false
 }
 
-// ReadAny implements HexEWKBDecoder.
-func (h *hexEWKBDecoder) ReadAny(v interface{}) error {
-	if err := binary.Read(h.buf, h.byteOrder, v); err != nil {
+// Read implements io.Reader, decoding len(p) bytes into p.
+func (c *hexCursor) Read(p []byte) (int, error) {
+	if c.pos >= len(c.data) {
+		return 0, io.EOF
+	}
+
+	if c.raw {
+		n := copy(p, c.data[c.pos:])
+		c.pos += n
+		return n, nil
+	}
+
+	n := 0
+	for n < len(p) && c.pos < len(c.data) {
+		hi, ok := unhex(c.data[c.pos])
+		if !ok {
+			return n, hex.InvalidByteError(c.data[c.pos])
+		}
+		lo, ok := unhex(c.data[c.pos+1])
+		if !ok {
+			return n, hex.InvalidByteError(c.data[c.pos+1])
+		}
+		p[n] = hi<<4 | lo
+		c.pos += 2
+		n++
+	}
+
+	return n, nil
+}
+
+// Decodes exactly len(p) bytes into p.
+func (c *hexCursor) readFull(p []byte) error {
+	n, err := c.Read(p)
+	if err != nil {
 		return err
 	}
 
+	if n < len(p) {
+		return io.ErrUnexpectedEOF
+	}
+
 	return nil
 }
 
-// ReadByte implements HexEWKBDecoder.
-func (h *hexEWKBDecoder) ReadByte() (byte, error) {
-	var v uint8
-	if err := binary.Read(h.buf, h.byteOrder, &v); err != nil {
+func (c *hexCursor) readByte() (byte, error) {
+	var b [1]byte
+	if err := c.readFull(b[:]); err != nil {
 		return 0, err
 	}
 
-	return v, nil
+	return b[0], nil
 }
 
-// ReadDouble implements HexEWKBDecoder.
-func (h *hexEWKBDecoder) ReadDouble() (float64, error) {
-	var v float64
-	if err := binary.Read(h.buf, h.byteOrder, &v); err != nil {
+func (c *hexCursor) readUint32() (uint32, error) {
+	var b [4]byte
+	if err := c.readFull(b[:]); err != nil {
 		return 0, err
 	}
 
-	return v, nil
+	if c.little {
+		return binary.LittleEndian.Uint32(b[:]), nil
+	}
+
+	return binary.BigEndian.Uint32(b[:]), nil
 }
 
-// ReadUint32 implements HexEWKBDecoder.
-func (h *hexEWKBDecoder) ReadUint32() (uint32, error) {
-	var v uint32
-	if err := binary.Read(h.buf, h.byteOrder, &v); err != nil {
+func (c *hexCursor) readDouble() (float64, error) {
+	var b [8]byte
+	if err := c.readFull(b[:]); err != nil {
 		return 0, err
 	}
 
-	return v, nil
+	if c.little {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+	}
+
+	return math.Float64frombits(binary.BigEndian.Uint64(b[:])), nil
+}
+
+// Reads len(values) doubles into values.
+func (c *hexCursor) readDoubles(values []float64) error {
+	for i := range values {
+		v, err := c.readDouble()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		values[i] = v
+	}
+
+	return nil
+}
+
+type hexEWKBDecoder struct {
+	hexCursor
+}
+
+// ReadAny implements HexEWKBDecoder.
+func (h *hexEWKBDecoder) ReadAny(v interface{}) error {
+	switch t := v.(type) {
+	case []float64:
+		return h.readDoubles(t)
+
+	case *float64:
+		d, err := h.readDouble()
+		if err != nil {
+			return err
+		}
+		*t = d
+		return nil
+
+	case *uint32:
+		d, err := h.readUint32()
+		if err != nil {
+			return err
+		}
+		*t = d
+		return nil
+	}
+
+	return binary.Read(&h.hexCursor, h.order, v)
+}
+
+// ReadByte implements HexEWKBDecoder.
+func (h *hexEWKBDecoder) ReadByte() (byte, error) {
+	return h.readByte()
+}
+
+// ReadDouble implements HexEWKBDecoder.
+func (h *hexEWKBDecoder) ReadDouble() (float64, error) {
+	return h.readDouble()
+}
+
+// ReadUint32 implements HexEWKBDecoder.
+func (h *hexEWKBDecoder) ReadUint32() (uint32, error) {
+	return h.readUint32()
 }
 
 // Type implements HexEWKBDecoder.
@@ -51,3 +51,53 @@ func TestBigEndian(t *testing.T) {
 	s := "000000000140240000000000004034000000000000"
 	testHex(s, t)
 }
+
+func TestOddLength(t *testing.T) {
+	if _, e := gopostgis.NewHexEWKBDecoder([]byte("0101000")); e == nil {
+		t.Error("should not construct a decoder for odd length data")
+	}
+}
+
+func TestInvalidHex(t *testing.T) {
+	d, e := gopostgis.NewHexEWKBDecoder([]byte("0101000000000000000000244000000000000034ZZ"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if _, e := d.ReadDouble(); e != nil {
+		t.Error(e)
+	}
+
+	if _, e := d.ReadDouble(); e == nil {
+		t.Error("should fail on invalid hex digit")
+	}
+}
+
+func TestShortRead(t *testing.T) {
+	// POINT(10 20) missing the last byte
+	d, e := gopostgis.NewHexEWKBDecoder([]byte("0101000000000000000000244000000000000034"))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	xy := make([]float64, 2)
+	if e := d.ReadAny(xy); e == nil {
+		t.Error("should fail on short data")
+	}
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	// POINT(10 20)
+	s := []byte("010100000000000000000024400000000000003440")
+	xy := make([]float64, 2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d, e := gopostgis.NewHexEWKBDecoder(s)
+		if e != nil {
+			b.Fatal(e)
+		}
+		if e := d.ReadAny(xy); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gopostgis
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// EWKBMarshaler is implemented by the datatypes which can encode
+// themselves into binary EWKB, the format postgis uses for geometry
+// in binary protocols, e.g. COPY BINARY.
+// NULL values are encoded as a nil slice.
+type EWKBMarshaler interface {
+	MarshalEWKB() ([]byte, error)
+}
+
+// Appends EWKB of d in little endian byte order, snapped to the grid of
+// SetPrecision. EMPTY points are encoded with NaN coordinates.
+func (d pointData) appendEWKB(b []byte) ([]byte, error) {
+	d = d.withPrecision()
+	empty, e := d.check()
+	if e != nil {
+		return nil, e
+	}
+
+	t := WKBPoint | d.layout.flags
+	if d.layout.srid {
+		t |= ewkbSRIDFlag
+	}
+
+	b = append(b, 0x01)
+	b = appendUint32(b, t)
+	if d.layout.srid {
+		b = appendUint32(b, d.srid)
+	}
+
+	for _, c := range d.values() {
+		if empty {
+			c = math.NaN()
+		}
+		b = appendDouble(b, c)
+	}
+
+	return b, nil
+}
+
+// Appends ISO WKB of d in little endian byte order. ISO WKB has no SRID
+// and encodes the dimensions as offsets of the geometry type.
+func (d pointData) appendWKB(b []byte, empty bool) []byte {
+	t := WKBPoint
+	if d.layout.flags&ewkbZFlag != 0 {
+		t += 1000
+	}
+	if d.layout.flags&ewkbMFlag != 0 {
+		t += 2000
+	}
+
+	b = append(b, 0x01)
+	b = appendUint32(b, t)
+
+	for _, c := range d.values() {
+		if empty {
+			c = math.NaN()
+		}
+		b = appendDouble(b, c)
+	}
+
+	return b
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendDouble(b []byte, v float64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+	return append(b, buf[:]...)
+}
+
+// Implements EWKBMarshaler for every point type.
+func (d pointData) marshalEWKB() ([]byte, error) {
+	if !d.valid {
+		return nil, nil
+	}
+
+	return d.appendEWKB(make([]byte, 0, 9+8*d.layout.dims()))
+}
@@ -0,0 +1,135 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Envelope is an axis aligned bounding box, same as postgis box2d.
+type Envelope struct {
+	MinX float64
+	MinY float64
+	MaxX float64
+	MaxY float64
+}
+
+// EnvelopeOf returns the envelope of geometry g, which is any point type
+// or a slice, possibly nested, of point types. NULL and EMPTY points are
+// skipped, a geometry without any point is an error.
+func EnvelopeOf(g any) (Envelope, error) {
+	e := Envelope{
+		MinX: math.Inf(1),
+		MinY: math.Inf(1),
+		MaxX: math.Inf(-1),
+		MaxY: math.Inf(-1),
+	}
+
+	if err := e.extend(reflect.ValueOf(g)); err != nil {
+		return Envelope{}, err
+	}
+
+	if e.MinX > e.MaxX {
+		return Envelope{}, fmt.Errorf("envelope of NULL or EMPTY geometry")
+	}
+
+	return e, nil
+}
+
+var pointDataType = reflect.TypeOf((*interface{ data() pointData })(nil)).Elem()
+
+func (e *Envelope) extend(v reflect.Value) error {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return fmt.Errorf("envelope of nil geometry")
+	}
+
+	if v.Type().Implements(pointDataType) {
+		d := v.Interface().(interface{ data() pointData }).data()
+		if !d.valid || d.empty {
+			return nil
+		}
+		e.add(d.coords[0], d.coords[1])
+		return nil
+	}
+
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("geometry type not supported. got: %v", v.Type())
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := e.extend(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Envelope) add(x, y float64) {
+	e.MinX = math.Min(e.MinX, x)
+	e.MinY = math.Min(e.MinY, y)
+	e.MaxX = math.Max(e.MaxX, x)
+	e.MaxY = math.Max(e.MaxY, y)
+}
+
+// Intersects reports whether e and o share any point, same as the
+// postgis && operator.
+func (e Envelope) Intersects(o Envelope) bool {
+	return e.MinX <= o.MaxX && o.MinX <= e.MaxX && e.MinY <= o.MaxY && o.MinY <= e.MaxY
+}
+
+// Contains reports whether o lies completely inside e, same as the
+// postgis ~ operator.
+func (e Envelope) Contains(o Envelope) bool {
+	return e.MinX <= o.MinX && o.MaxX <= e.MaxX && e.MinY <= o.MinY && o.MaxY <= e.MaxY
+}
+
+// Union returns the smallest envelope containing both e and o.
+func (e Envelope) Union(o Envelope) Envelope {
+	return Envelope{
+		MinX: math.Min(e.MinX, o.MinX),
+		MinY: math.Min(e.MinY, o.MinY),
+		MaxX: math.Max(e.MaxX, o.MaxX),
+		MaxY: math.Max(e.MaxY, o.MaxY),
+	}
+}
+
+// Area of e.
+func (e Envelope) Area() float64 {
+	return (e.MaxX - e.MinX) * (e.MaxY - e.MinY)
+}
+
+// Distance returns the planar distance of point x, y from e,
+// zero if the point is inside e.
+func (e Envelope) Distance(x, y float64) float64 {
+	return math.Hypot(axisDistance(x, e.MinX, e.MaxX), axisDistance(y, e.MinY, e.MaxY))
+}
+
+func axisDistance(v, min, max float64) float64 {
+	switch {
+	case v < min:
+		return min - v
+	case v > max:
+		return v - max
+	}
+
+	return 0
+}
+
+// Calls fn with every pair i < j of intersecting envelopes, found with
+// an R-tree over envs.
+func intersectingPairs(envs []Envelope, fn func(i, j int)) {
+	entries := make([]rtreeEntry[int], len(envs))
+	for i, e := range envs {
+		entries[i] = rtreeEntry[int]{env: e, value: i}
+	}
+	tree := bulkLoad(entries)
+
+	for i, e := range envs {
+		tree.visit(e, func(o rtreeEntry[int]) {
+			if o.value > i {
+				fn(i, o.value)
+			}
+		})
+	}
+}
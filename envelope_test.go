@@ -0,0 +1,71 @@
+package gopostgis_test
+
+import (
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestEnvelope(t *testing.T) {
+	t.Run("of point", func(t *testing.T) {
+		found, e := gopostgis.EnvelopeOf(gopostgis.PointZS{SRID: 4326, X: 1, Y: 2, Z: 3, Valid: true})
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := gopostgis.Envelope{MinX: 1, MinY: 2, MaxX: 1, MaxY: 2}
+		if found != expected {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("of slices", func(t *testing.T) {
+		rings := [][]gopostgis.Point{
+			{{X: 0, Y: 0, Valid: true}, {X: 4, Y: -1, Valid: true}},
+			{{Empty: true, Valid: true}, {X: -2, Y: 5, Valid: true}},
+		}
+		found, e := gopostgis.EnvelopeOf(rings)
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := gopostgis.Envelope{MinX: -2, MinY: -1, MaxX: 4, MaxY: 5}
+		if found != expected {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, g := range []any{
+			nil,
+			gopostgis.Point{},
+			[]gopostgis.PointM{},
+			[]gopostgis.Point{{Empty: true, Valid: true}},
+			"POINT(1 2)",
+			(*gopostgis.Point)(nil),
+		} {
+			if _, e := gopostgis.EnvelopeOf(g); e == nil {
+				t.Error("expected error for:", g)
+			}
+		}
+	})
+
+	t.Run("predicates", func(t *testing.T) {
+		a := gopostgis.Envelope{MinX: 0, MinY: 0, MaxX: 4, MaxY: 4}
+		b := gopostgis.Envelope{MinX: 4, MinY: 4, MaxX: 6, MaxY: 6}
+		c := gopostgis.Envelope{MinX: 1, MinY: 1, MaxX: 2, MaxY: 2}
+		if !a.Intersects(b) || a.Intersects(gopostgis.Envelope{MinX: 5, MinY: 5, MaxX: 6, MaxY: 6}) {
+			t.Error("unexpected intersects result")
+		}
+		if !a.Contains(c) || a.Contains(b) {
+			t.Error("unexpected contains result")
+		}
+		if u := a.Union(b); u != (gopostgis.Envelope{MinX: 0, MinY: 0, MaxX: 6, MaxY: 6}) || u.Area() != 36 {
+			t.Error("unexpected union:", u)
+		}
+		if d := a.Distance(7, 8); d != 5 {
+			t.Error("expected: 5 found:", d)
+		}
+		if d := a.Distance(2, 2); d != 0 {
+			t.Error("expected: 0 found:", d)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+)
+
+// Geohash encoding of longitude/latitude points, same as postgis
+// ST_GeoHash and ST_PointFromGeoHash.
+// reference - https://en.wikipedia.org/wiki/Geohash
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Maximum geohash precision, finer precisions exceed float64 resolution.
+const MaxGeohashPrecision = 12
+
+// GeohashBox is the bounding box of a geohash in degrees.
+type GeohashBox struct {
+	MinX float64
+	MinY float64
+	MaxX float64
+	MaxY float64
+}
+
+// Center returns the center of the box.
+func (b GeohashBox) Center() Point {
+	return Point{
+		X:     (b.MinX + b.MaxX) / 2,
+		Y:     (b.MinY + b.MaxY) / 2,
+		Valid: true,
+	}
+}
+
+// GeohashDirection selects a neighbor in [GeohashNeighbor].
+type GeohashDirection int
+
+const (
+	GeohashNorth GeohashDirection = iota
+	GeohashNorthEast
+	GeohashEast
+	GeohashSouthEast
+	GeohashSouth
+	GeohashSouthWest
+	GeohashWest
+	GeohashNorthWest
+)
+
+// Geohash returns the geohash of p with precision characters.
+// X is the longitude and Y is the latitude.
+func (p Point) Geohash(precision int) (string, error) {
+	return p.data().geohash(precision)
+}
+
+// Geohash returns the geohash of p with precision characters.
+// SRID of p must be 4326.
+func (p PointS) Geohash(precision int) (string, error) {
+	return p.data().geohash(precision)
+}
+
+func (d pointData) geohash(precision int) (string, error) {
+	if precision < 1 || precision > MaxGeohashPrecision {
+		return "", fmt.Errorf("geohash precision out of range. got: %d", precision)
+	}
+
+	if !d.valid || d.empty {
+		return "", fmt.Errorf("geohash of NULL or EMPTY point")
+	}
+
+	if d.layout.srid && d.srid != 4326 {
+		return "", fmt.Errorf("geohash srid must be 4326. got: %d", d.srid)
+	}
+
+	lng, lat := d.coords[0], d.coords[1]
+	if !(lng >= -180 && lng <= 180 && lat >= -90 && lat <= 90) {
+		return "", fmt.Errorf("%w: %g %g not in srid 4326", ErrOutOfBounds, lng, lat)
+	}
+
+	return encodeGeohash(lng, lat, precision), nil
+}
+
+func encodeGeohash(lng, lat float64, precision int) string {
+	minLng, maxLng := -180.0, 180.0
+	minLat, maxLat := -90.0, 90.0
+
+	b := make([]byte, precision)
+	even := true
+	for i := range b {
+		var ch byte
+		for bit := 4; bit >= 0; bit-- {
+			if even {
+				mid := (minLng + maxLng) / 2
+				if lng >= mid {
+					ch |= 1 << bit
+					minLng = mid
+				} else {
+					maxLng = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if lat >= mid {
+					ch |= 1 << bit
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+		b[i] = geohashAlphabet[ch]
+	}
+
+	return string(b)
+}
+
+// GeohashBounds returns the bounding box of hash.
+func GeohashBounds(hash string) (GeohashBox, error) {
+	if len(hash) == 0 || len(hash) > MaxGeohashPrecision {
+		return GeohashBox{}, fmt.Errorf("invalid geohash length. got: %d", len(hash))
+	}
+
+	box := GeohashBox{MinX: -180, MinY: -90, MaxX: 180, MaxY: 90}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		ch := geohashIndex(hash[i])
+		if ch < 0 {
+			return GeohashBox{}, fmt.Errorf("invalid geohash character. got: %q", hash[i])
+		}
+
+		for bit := 4; bit >= 0; bit-- {
+			set := ch&(1<<bit) != 0
+			if even {
+				mid := (box.MinX + box.MaxX) / 2
+				if set {
+					box.MinX = mid
+				} else {
+					box.MaxX = mid
+				}
+			} else {
+				mid := (box.MinY + box.MaxY) / 2
+				if set {
+					box.MinY = mid
+				} else {
+					box.MaxY = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return box, nil
+}
+
+// Index of c in the alphabet, case insensitive. -1 if not found.
+func geohashIndex(c byte) int {
+	if 'A' <= c && c <= 'Z' {
+		c += 'a' - 'A'
+	}
+
+	for i := 0; i < len(geohashAlphabet); i++ {
+		if geohashAlphabet[i] == c {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// DecodeGeohash returns the center of hash with SRID 4326 along with the
+// error bounds, i.e. half of the width and height of its box in degrees.
+func DecodeGeohash(hash string) (PointS, float64, float64, error) {
+	box, err := GeohashBounds(hash)
+	if err != nil {
+		return PointS{}, 0, 0, err
+	}
+
+	c := box.Center()
+	p := PointS{SRID: 4326, X: c.X, Y: c.Y, Valid: true}
+
+	return p, (box.MaxX - box.MinX) / 2, (box.MaxY - box.MinY) / 2, nil
+}
+
+// GeohashNeighbor returns the adjacent geohash of the same precision in
+// direction dir. Longitude wraps around the antimeridian, there is no
+// neighbor beyond the poles and an empty string is returned.
+func GeohashNeighbor(hash string, dir GeohashDirection) (string, error) {
+	box, err := GeohashBounds(hash)
+	if err != nil {
+		return "", err
+	}
+
+	var dx, dy float64
+	switch dir {
+	case GeohashNorth:
+		dy = 1
+	case GeohashNorthEast:
+		dx, dy = 1, 1
+	case GeohashEast:
+		dx = 1
+	case GeohashSouthEast:
+		dx, dy = 1, -1
+	case GeohashSouth:
+		dy = -1
+	case GeohashSouthWest:
+		dx, dy = -1, -1
+	case GeohashWest:
+		dx = -1
+	case GeohashNorthWest:
+		dx, dy = -1, 1
+	default:
+		return "", fmt.Errorf("invalid geohash direction. got: %d", dir)
+	}
+
+	c := box.Center()
+	lng := c.X + dx*(box.MaxX-box.MinX)
+	lat := c.Y + dy*(box.MaxY-box.MinY)
+
+	if lat > 90 || lat < -90 {
+		return "", nil
+	}
+
+	lng = math.Mod(lng+540, 360) - 180
+
+	return encodeGeohash(lng, lat, len(hash)), nil
+}
+
+// GeohashNeighbors returns the 8 neighbors of hash, in the order of
+// [GeohashDirection] constants.
+func GeohashNeighbors(hash string) ([8]string, error) {
+	var n [8]string
+	for dir := GeohashNorth; dir <= GeohashNorthWest; dir++ {
+		h, err := GeohashNeighbor(hash, dir)
+		if err != nil {
+			return n, err
+		}
+		n[dir] = h
+	}
+
+	return n, nil
+}
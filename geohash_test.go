@@ -0,0 +1,117 @@
+package gopostgis_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestGeohash(t *testing.T) {
+	t.Run("encode", func(t *testing.T) {
+		// ST_GeoHash(ST_Point(-126, 48), 12)
+		p := gopostgis.Point{X: -126, Y: 48, Valid: true}
+		found, e := p.Geohash(12)
+		expected := "c0w3hf1s70w3"
+		if e != nil {
+			t.Error(e)
+		}
+		if found != expected {
+			t.Error("expected:", expected, "found:", found)
+		}
+
+		ps := gopostgis.PointS{SRID: 4326, X: -5.6, Y: 42.6, Valid: true}
+		found, e = ps.Geohash(5)
+		expected = "ezs42"
+		if e != nil {
+			t.Error(e)
+		}
+		if found != expected {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("encode errors", func(t *testing.T) {
+		if _, e := (gopostgis.PointS{SRID: 3857, Valid: true}).Geohash(5); e == nil {
+			t.Error("srid 3857 should fail")
+		}
+		if _, e := (gopostgis.Point{X: 181, Valid: true}).Geohash(5); !errors.Is(e, gopostgis.ErrOutOfBounds) {
+			t.Error("expected:", gopostgis.ErrOutOfBounds, "found:", e)
+		}
+		if _, e := (gopostgis.Point{}).Geohash(5); e == nil {
+			t.Error("null point should fail")
+		}
+		if _, e := (gopostgis.Point{Valid: true}).Geohash(13); e == nil {
+			t.Error("precision 13 should fail")
+		}
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		p, errX, errY, e := gopostgis.DecodeGeohash("ezs42")
+		if e != nil {
+			t.Fatal(e)
+		}
+		if math.Abs(p.X-(-5.60302734375)) > 1e-12 || math.Abs(p.Y-42.60498046875) > 1e-12 || !p.Valid {
+			t.Error("unexpected center:", p)
+		}
+		if p.SRID != 4326 {
+			t.Error("expected: 4326 found:", p.SRID)
+		}
+		if h, e := p.Geohash(5); e != nil || h != "ezs42" {
+			t.Error("expected: ezs42 found:", h, e)
+		}
+		if errX != 0.02197265625 || errY != 0.02197265625 {
+			t.Error("unexpected error bounds:", errX, errY)
+		}
+
+		if _, _, _, e := gopostgis.DecodeGeohash("ezs4a"); e == nil {
+			t.Error("invalid character should fail")
+		}
+		if _, _, _, e := gopostgis.DecodeGeohash(""); e == nil {
+			t.Error("empty geohash should fail")
+		}
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		box, e := gopostgis.GeohashBounds("EZS42")
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := gopostgis.GeohashBox{MinX: -5.625, MinY: 42.5830078125, MaxX: -5.5810546875, MaxY: 42.626953125}
+		if box != expected {
+			t.Error("expected:", expected, "found:", box)
+		}
+	})
+
+	t.Run("neighbors", func(t *testing.T) {
+		found, e := gopostgis.GeohashNeighbors("gbsuv")
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := [8]string{"gbsvj", "gbsvn", "gbsuy", "gbsuw", "gbsut", "gbsus", "gbsuu", "gbsvh"}
+		if found != expected {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("neighbors edges", func(t *testing.T) {
+		// wraps around the antimeridian
+		found, e := gopostgis.GeohashNeighbor("8", gopostgis.GeohashWest)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if found != "x" {
+			t.Error("expected: x found:", found)
+		}
+
+		// nothing beyond the north pole
+		found, e = gopostgis.GeohashNeighbor("b", gopostgis.GeohashNorth)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if found != "" {
+			t.Error("expected no neighbor found:", found)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package gopostgis
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// GeoPackage binary geometry, a header followed by ISO WKB.
+// reference - https://www.geopackage.org/spec/#gpb_format
+
+// GPKGEnvelope is the envelope type of a GeoPackage binary header.
+type GPKGEnvelope byte
+
+const (
+	GPKGEnvelopeNone GPKGEnvelope = iota
+	GPKGEnvelopeXY
+	GPKGEnvelopeXYZ
+	GPKGEnvelopeXYM
+	GPKGEnvelopeXYZM
+)
+
+// Number of values of an envelope of type e.
+func (e GPKGEnvelope) size() int {
+	switch e {
+	case GPKGEnvelopeXY:
+		return 4
+	case GPKGEnvelopeXYZ, GPKGEnvelopeXYM:
+		return 6
+	case GPKGEnvelopeXYZM:
+		return 8
+	}
+
+	return 0
+}
+
+const (
+	gpkgFlagLittle   = 0x01
+	gpkgFlagEmpty    = 0x10
+	gpkgFlagExtended = 0x20
+)
+
+// GPKGHeader is the header of a GeoPackage binary geometry.
+//
+// Envelope holds minx, maxx, miny, maxy followed by minz, maxz and/or
+// minm, maxm according to EnvelopeType.
+type GPKGHeader struct {
+	Version      byte
+	SRID         int32
+	Empty        bool
+	Extended     bool
+	EnvelopeType GPKGEnvelope
+	Envelope     []float64
+}
+
+// ReadGPKGHeader reads the header of GeoPackage binary geometry b,
+// returns it along with the WKB following it.
+func ReadGPKGHeader(b []byte) (GPKGHeader, []byte, error) {
+	var h GPKGHeader
+
+	if len(b) < 8 {
+		return h, nil, fmt.Errorf("gpkg header too short. got: %d bytes", len(b))
+	}
+
+	if b[0] != 'G' || b[1] != 'P' {
+		return h, nil, fmt.Errorf("invalid gpkg magic. got: %q", b[:2])
+	}
+
+	flags := b[3]
+	h.Version = b[2]
+	h.Empty = flags&gpkgFlagEmpty != 0
+	h.Extended = flags&gpkgFlagExtended != 0
+	h.EnvelopeType = GPKGEnvelope((flags >> 1) & 0x07)
+	if h.EnvelopeType > GPKGEnvelopeXYZM {
+		return h, nil, fmt.Errorf("invalid gpkg envelope type. got: %d", h.EnvelopeType)
+	}
+
+	var order binary.ByteOrder = binary.BigEndian
+	if flags&gpkgFlagLittle != 0 {
+		order = binary.LittleEndian
+	}
+
+	h.SRID = int32(order.Uint32(b[4:]))
+
+	n := h.EnvelopeType.size()
+	if len(b) < 8+8*n {
+		return h, nil, fmt.Errorf("gpkg envelope too short. got: %d bytes", len(b)-8)
+	}
+
+	if n > 0 {
+		h.Envelope = make([]float64, n)
+		for i := range h.Envelope {
+			h.Envelope[i] = math.Float64frombits(order.Uint64(b[8+8*i:]))
+		}
+	}
+
+	return h, b[8+8*n:], nil
+}
+
+// AppendGPKGHeader appends header h in little endian byte order to b.
+func AppendGPKGHeader(b []byte, h GPKGHeader) ([]byte, error) {
+	if len(h.Envelope) != h.EnvelopeType.size() {
+		return nil, fmt.Errorf("gpkg envelope size mismatch. got: %d", len(h.Envelope))
+	}
+
+	flags := byte(h.EnvelopeType)<<1 | gpkgFlagLittle
+	if h.Empty {
+		flags |= gpkgFlagEmpty
+	}
+	if h.Extended {
+		flags |= gpkgFlagExtended
+	}
+
+	b = append(b, 'G', 'P', h.Version, flags)
+	b = appendUint32(b, uint32(h.SRID))
+	for _, v := range h.Envelope {
+		b = appendDouble(b, v)
+	}
+
+	return b, nil
+}
+
+// Dimensions of WKB geometry type t, either EWKB flags or ISO offsets.
+func wkbDims(t uint32) (bool, bool) {
+	if t&(ewkbZFlag|ewkbMFlag) != 0 {
+		return t&ewkbZFlag != 0, t&ewkbMFlag != 0
+	}
+
+	switch (t &^ ewkbSRIDFlag) / 1000 {
+	case 1:
+		return true, false
+	case 2:
+		return false, true
+	case 3:
+		return true, true
+	}
+
+	return false, false
+}
+
+// Implements MarshalGPKG for every point type. Points are written
+// without an envelope, EMPTY points with NaN coordinates.
+func (d pointData) marshalGPKG() ([]byte, error) {
+	if !d.valid {
+		return nil, nil
+	}
+
+	d = d.withPrecision()
+	empty, e := d.check()
+	if e != nil {
+		return nil, e
+	}
+
+	h := GPKGHeader{
+		SRID:  int32(d.srid),
+		Empty: empty,
+	}
+
+	b, e := AppendGPKGHeader(make([]byte, 0, 17+8*d.layout.dims()), h)
+	if e != nil {
+		return nil, e
+	}
+
+	return d.appendWKB(b, empty), nil
+}
+
+// Implements UnmarshalGPKG for every point type.
+func unmarshalGPKG(b []byte, l pointLayout) (pointData, error) {
+	d := pointData{layout: l}
+	if b == nil {
+		return d, nil
+	}
+
+	h, wkb, e := ReadGPKGHeader(b)
+	if e != nil {
+		return d, e
+	}
+
+	var c hexCursor
+	if e := c.initRaw(wkb); e != nil {
+		return d, e
+	}
+
+	z, m := wkbDims(c.dType)
+	if hasZ, hasM := l.flags&ewkbZFlag != 0, l.flags&ewkbMFlag != 0; z != hasZ || m != hasM {
+		return d, fmt.Errorf("gpkg geometry dimension mismatch. got: %v", c.dType)
+	}
+
+	// GeoPackage WKB has no SRID, it lives in the header
+	_, empty, e := readPoint(&c, c.dType&ewkbSRIDFlag != 0, d.values())
+	if e != nil {
+		return d, e
+	}
+
+	if l.srid && h.SRID > 0 {
+		d.srid = uint32(h.SRID)
+	}
+
+	d.empty = empty || h.Empty
+	if d.empty {
+		d.coords = [4]float64{}
+	}
+	d.valid = true
+
+	return d, nil
+}
@@ -0,0 +1,161 @@
+package gopostgis_test
+
+import (
+	"encoding/hex"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, e := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if e != nil {
+		t.Fatal(e)
+	}
+	return b
+}
+
+func TestGPKG(t *testing.T) {
+	t.Run("marshal", func(t *testing.T) {
+		found, e := gopostgis.PointS{SRID: 4326, X: 1, Y: 2, Valid: true}.MarshalGPKG()
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := "47500001e6100000" + "0101000000000000000000f03f0000000000000040"
+		if hex.EncodeToString(found) != expected {
+			t.Error("expected:", expected, "found:", hex.EncodeToString(found))
+		}
+
+		found, e = gopostgis.PointZM{X: 1, Y: 2, Z: 3, M: 4, Valid: true}.MarshalGPKG()
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected = "4750000100000000" + "01b90b0000000000000000f03f000000000000004000000000000008400000000000001040"
+		if hex.EncodeToString(found) != expected {
+			t.Error("expected:", expected, "found:", hex.EncodeToString(found))
+		}
+	})
+
+	t.Run("marshal empty and null", func(t *testing.T) {
+		found, e := gopostgis.PointS{SRID: 4326, Empty: true, Valid: true}.MarshalGPKG()
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := "47500011e6100000" + "0101000000010000000000f87f010000000000f87f"
+		if hex.EncodeToString(found) != expected {
+			t.Error("expected:", expected, "found:", hex.EncodeToString(found))
+		}
+
+		found, e = gopostgis.PointS{}.MarshalGPKG()
+		if e != nil || found != nil {
+			t.Error("expected: nil found:", found, e)
+		}
+	})
+
+	t.Run("unmarshal big endian with envelope", func(t *testing.T) {
+		b := mustHex(t, "47500002 000010e6"+
+			"3ff0000000000000 3ff0000000000000 4000000000000000 4000000000000000"+
+			"00 00000001 3ff0000000000000 4000000000000000")
+		var p gopostgis.PointS
+		if e := p.UnmarshalGPKG(b); e != nil {
+			t.Fatal(e)
+		}
+		expected := gopostgis.PointS{SRID: 4326, X: 1, Y: 2, Valid: true}
+		if p != expected {
+			t.Error("expected:", expected, "found:", p)
+		}
+
+		h, wkb, e := gopostgis.ReadGPKGHeader(b)
+		if e != nil {
+			t.Fatal(e)
+		}
+		expectedHeader := gopostgis.GPKGHeader{SRID: 4326, EnvelopeType: gopostgis.GPKGEnvelopeXY, Envelope: []float64{1, 1, 2, 2}}
+		if !reflect.DeepEqual(h, expectedHeader) || len(wkb) != 21 {
+			t.Error("expected:", expectedHeader, "found:", h, len(wkb))
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		in := gopostgis.PointZMS{SRID: 3857, X: 1.5, Y: -2.5, Z: 10, M: 20, Valid: true}
+		b, e := in.MarshalGPKG()
+		if e != nil {
+			t.Fatal(e)
+		}
+		var out gopostgis.PointZMS
+		if e := out.UnmarshalGPKG(b); e != nil {
+			t.Fatal(e)
+		}
+		if out != in {
+			t.Error("expected:", in, "found:", out)
+		}
+
+		empty := gopostgis.PointM{Empty: true, Valid: true}
+		if b, e = empty.MarshalGPKG(); e != nil {
+			t.Fatal(e)
+		}
+		var outM gopostgis.PointM
+		if e := outM.UnmarshalGPKG(b); e != nil {
+			t.Fatal(e)
+		}
+		if outM != empty {
+			t.Error("expected:", empty, "found:", outM)
+		}
+	})
+
+	t.Run("unmarshal null", func(t *testing.T) {
+		p := gopostgis.Point{X: 1, Valid: true}
+		if e := p.UnmarshalGPKG(nil); e != nil {
+			t.Fatal(e)
+		}
+		if p != (gopostgis.Point{}) {
+			t.Error("expected NULL found:", p)
+		}
+	})
+
+	t.Run("append header", func(t *testing.T) {
+		h := gopostgis.GPKGHeader{SRID: -1, EnvelopeType: gopostgis.GPKGEnvelopeXYZ, Envelope: []float64{0, 1, 0, 1, 0, 1}}
+		b, e := gopostgis.AppendGPKGHeader(nil, h)
+		if e != nil {
+			t.Fatal(e)
+		}
+		found, _, e := gopostgis.ReadGPKGHeader(b)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(found, h) {
+			t.Error("expected:", h, "found:", found)
+		}
+
+		h.Envelope = h.Envelope[:4]
+		if _, e := gopostgis.AppendGPKGHeader(nil, h); e == nil {
+			t.Error("envelope size mismatch should fail")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		valid := "47500001e6100000" + "0101000000000000000000f03f0000000000000040"
+		cases := []string{
+			"4750",
+			"4751" + valid[4:],
+			"4750000fe6100000" + valid[16:],
+			"47500003e6100000" + valid[16:],
+			valid[:len(valid)-2],
+			"47500001e6100000" + "0102000000" + "00000000",
+			// 3d point into a 2d type
+			"4750000100000000" + "01e9030000000000000000f03f00000000000000400000000000000840",
+		}
+		for _, c := range cases {
+			var p gopostgis.PointS
+			if e := p.UnmarshalGPKG(mustHex(t, c)); e == nil {
+				t.Error("expected error for:", c)
+			}
+		}
+
+		if _, e := (gopostgis.Point{X: math.NaN(), Y: 1, Valid: true}).MarshalGPKG(); e == nil {
+			t.Error("NaN coordinate should fail")
+		}
+	})
+}
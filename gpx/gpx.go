@@ -0,0 +1,296 @@
+// Package gpx converts GPX 1.1 documents to and from go-postgis types.
+// reference - https://www.topografix.com/GPX/1/1/
+//
+// Every GPX point becomes a [gopostgis.PointZMS] of SRID 4326, with X as
+// longitude, Y as latitude, Z as elevation in meters and M as the time
+// in unix seconds with millisecond precision. Waypoints, routes and tracks
+// tell whether their points have elevation and time, an M of 0 is written
+// as no time.
+package gpx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// Waypoint of a GPX document.
+type Waypoint struct {
+	Name         string
+	Description  string
+	Point        gopostgis.PointZMS
+	HasElevation bool // Z of Point is an elevation
+	HasTime      bool // M of Point is a time
+}
+
+// Route of a GPX document.
+type Route struct {
+	Name         string
+	Points       []gopostgis.PointZMS
+	HasElevation bool // Z of Points are elevations
+	HasTime      bool // M of Points are times
+}
+
+// Track of a GPX document, made of segments of points.
+type Track struct {
+	Name         string
+	Segments     [][]gopostgis.PointZMS
+	HasElevation bool // Z of the points of Segments are elevations
+	HasTime      bool // M of the points of Segments are times
+}
+
+// Document is the content of a GPX file.
+type Document struct {
+	Waypoints []Waypoint
+	Routes    []Route
+	Tracks    []Track
+}
+
+type gpxPoint struct {
+	Lat  float64  `xml:"lat,attr"`
+	Lon  float64  `xml:"lon,attr"`
+	Ele  *float64 `xml:"ele,omitempty"`
+	Time string   `xml:"time,omitempty"`
+	Name string   `xml:"name,omitempty"`
+	Desc string   `xml:"desc,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxFile struct {
+	XMLName   xml.Name   `xml:"gpx"`
+	Xmlns     string     `xml:"xmlns,attr,omitempty"`
+	Version   string     `xml:"version,attr"`
+	Creator   string     `xml:"creator,attr"`
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []gpxRoute `xml:"rte"`
+	Tracks    []gpxTrack `xml:"trk"`
+}
+
+// Decode reads a GPX document from r.
+func Decode(r io.Reader) (*Document, error) {
+	var f gpxFile
+	if err := xml.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+
+	var doc Document
+
+	for _, w := range f.Waypoints {
+		p, err := decodePoint(w)
+		if err != nil {
+			return nil, err
+		}
+		doc.Waypoints = append(doc.Waypoints, Waypoint{
+			Name:         w.Name,
+			Description:  w.Desc,
+			Point:        p,
+			HasElevation: w.Ele != nil,
+			HasTime:      w.Time != "",
+		})
+	}
+
+	for _, r := range f.Routes {
+		points, err := decodePoints(r.Points)
+		if err != nil {
+			return nil, err
+		}
+		doc.Routes = append(doc.Routes, Route{
+			Name:         r.Name,
+			Points:       points,
+			HasElevation: hasElevation(r.Points),
+			HasTime:      hasTime(r.Points),
+		})
+	}
+
+	for _, t := range f.Tracks {
+		track := Track{Name: t.Name}
+		for _, s := range t.Segments {
+			points, err := decodePoints(s.Points)
+			if err != nil {
+				return nil, err
+			}
+			track.Segments = append(track.Segments, points)
+			track.HasElevation = track.HasElevation || hasElevation(s.Points)
+			track.HasTime = track.HasTime || hasTime(s.Points)
+		}
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	return &doc, nil
+}
+
+func decodePoints(points []gpxPoint) ([]gopostgis.PointZMS, error) {
+	decoded := make([]gopostgis.PointZMS, 0, len(points))
+	for _, g := range points {
+		p, err := decodePoint(g)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, p)
+	}
+
+	return decoded, nil
+}
+
+// Tells whether any of points has an elevation.
+func hasElevation(points []gpxPoint) bool {
+	for _, g := range points {
+		if g.Ele != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tells whether any of points has a time.
+func hasTime(points []gpxPoint) bool {
+	for _, g := range points {
+		if g.Time != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodePoint(g gpxPoint) (gopostgis.PointZMS, error) {
+	p := gopostgis.PointZMS{
+		SRID:  4326,
+		X:     g.Lon,
+		Y:     g.Lat,
+		Valid: true,
+	}
+
+	if g.Ele != nil {
+		p.Z = *g.Ele
+	}
+
+	if g.Time != "" {
+		t, err := time.Parse(time.RFC3339Nano, g.Time)
+		if err != nil {
+			return p, fmt.Errorf("invalid gpx time: %w", err)
+		}
+		p.M = float64(t.Unix()) + float64(t.Nanosecond())/1e9
+	}
+
+	return p, nil
+}
+
+// Encode writes doc to w as a GPX document.
+func Encode(w io.Writer, doc *Document) error {
+	f := gpxFile{
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Version: "1.1",
+		Creator: "go-postgis",
+	}
+
+	for i, wp := range doc.Waypoints {
+		g, err := encodePoint(wp.Point, wp.HasElevation, wp.HasTime)
+		if err != nil {
+			return fmt.Errorf("waypoint %d: %w", i, err)
+		}
+		g.Name = wp.Name
+		g.Desc = wp.Description
+		f.Waypoints = append(f.Waypoints, g)
+	}
+
+	for i, r := range doc.Routes {
+		points, err := encodePoints(r.Points, r.HasElevation, r.HasTime)
+		if err != nil {
+			return fmt.Errorf("route %d: %w", i, err)
+		}
+		f.Routes = append(f.Routes, gpxRoute{
+			Name:   r.Name,
+			Points: points,
+		})
+	}
+
+	for i, t := range doc.Tracks {
+		track := gpxTrack{Name: t.Name}
+		for _, s := range t.Segments {
+			points, err := encodePoints(s, t.HasElevation, t.HasTime)
+			if err != nil {
+				return fmt.Errorf("track %d: %w", i, err)
+			}
+			track.Segments = append(track.Segments, gpxSegment{Points: points})
+		}
+		f.Tracks = append(f.Tracks, track)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(f); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func encodePoints(points []gopostgis.PointZMS, hasElevation, hasTime bool) ([]gpxPoint, error) {
+	encoded := make([]gpxPoint, 0, len(points))
+	for i, p := range points {
+		g, err := encodePoint(p, hasElevation, hasTime)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+		encoded = append(encoded, g)
+	}
+
+	return encoded, nil
+}
+
+func encodePoint(p gopostgis.PointZMS, hasElevation, hasTime bool) (gpxPoint, error) {
+	if !p.Valid || p.Empty {
+		return gpxPoint{}, fmt.Errorf("gpx can not have NULL or EMPTY points")
+	}
+
+	if p.SRID != 4326 {
+		return gpxPoint{}, fmt.Errorf("gpx srid must be 4326. got: %d", p.SRID)
+	}
+
+	if !p.IsValid() {
+		return gpxPoint{}, gopostgis.ErrNonFiniteCoordinate
+	}
+
+	g := gpxPoint{
+		Lat: p.Y,
+		Lon: p.X,
+	}
+
+	if hasElevation {
+		ele := p.Z
+		g.Ele = &ele
+	}
+
+	if hasTime && p.M != 0 {
+		// float64 unix seconds only hold about microseconds, round to milliseconds
+		ms := int64(math.Round(p.M * 1e3))
+		g.Time = time.UnixMilli(ms).UTC().Format(time.RFC3339Nano)
+	}
+
+	return g, nil
+}
@@ -0,0 +1,202 @@
+package gpx_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+	"github.com/asif-mahmud/go-postgis/gpx"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <wpt lat="52.5" lon="13.4">
+    <ele>34.5</ele>
+    <name>Camp</name>
+    <desc>Base camp</desc>
+  </wpt>
+  <rte>
+    <name>Route</name>
+    <rtept lat="52.5" lon="13.4"></rtept>
+    <rtept lat="52.6" lon="13.5"></rtept>
+  </rte>
+  <trk>
+    <name>Track</name>
+    <trkseg>
+      <trkpt lat="52.5" lon="13.4">
+        <ele>30</ele>
+        <time>2024-05-01T10:00:00Z</time>
+      </trkpt>
+      <trkpt lat="52.51" lon="13.41">
+        <ele>31.5</ele>
+        <time>2024-05-01T10:00:05.250Z</time>
+      </trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+func TestDecode(t *testing.T) {
+	doc, e := gpx.Decode(strings.NewReader(sample))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	t.Run("waypoints", func(t *testing.T) {
+		expected := []gpx.Waypoint{{
+			Name:         "Camp",
+			Description:  "Base camp",
+			Point:        gopostgis.PointZMS{SRID: 4326, X: 13.4, Y: 52.5, Z: 34.5, Valid: true},
+			HasElevation: true,
+		}}
+		if !reflect.DeepEqual(doc.Waypoints, expected) {
+			t.Error("expected:", expected, "found:", doc.Waypoints)
+		}
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		if len(doc.Routes) != 1 || doc.Routes[0].Name != "Route" || len(doc.Routes[0].Points) != 2 {
+			t.Fatal("unexpected routes:", doc.Routes)
+		}
+		expected := gopostgis.PointZMS{SRID: 4326, X: 13.5, Y: 52.6, Valid: true}
+		if doc.Routes[0].Points[1] != expected {
+			t.Error("expected:", expected, "found:", doc.Routes[0].Points[1])
+		}
+	})
+
+	t.Run("tracks", func(t *testing.T) {
+		if len(doc.Tracks) != 1 || len(doc.Tracks[0].Segments) != 1 {
+			t.Fatal("unexpected tracks:", doc.Tracks)
+		}
+		points := doc.Tracks[0].Segments[0]
+		if points[0].Z != 30 || points[0].M != 1714557600 {
+			t.Error("unexpected point:", points[0])
+		}
+		if points[1].Z != 31.5 || math.Abs(points[1].M-1714557605.25) > 1e-6 {
+			t.Error("unexpected point:", points[1])
+		}
+	})
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		doc, e := gpx.Decode(strings.NewReader(sample))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		var b bytes.Buffer
+		if e := gpx.Encode(&b, doc); e != nil {
+			t.Fatal(e)
+		}
+
+		found, e := gpx.Decode(&b)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(found, doc) {
+			t.Error("expected:", doc, "found:", found)
+		}
+	})
+
+	t.Run("output", func(t *testing.T) {
+		doc := &gpx.Document{
+			Waypoints: []gpx.Waypoint{{
+				Name:         "A",
+				Point:        gopostgis.PointZMS{SRID: 4326, X: 1, Y: 2, Z: 3, M: 1714557600.5, Valid: true},
+				HasElevation: true,
+				HasTime:      true,
+			}},
+		}
+
+		var b bytes.Buffer
+		if e := gpx.Encode(&b, doc); e != nil {
+			t.Fatal(e)
+		}
+
+		for _, expected := range []string{
+			`<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1" creator="go-postgis">`,
+			`<wpt lat="2" lon="1">`,
+			`<ele>3</ele>`,
+			`<time>2024-05-01T10:00:00.5Z</time>`,
+			`<name>A</name>`,
+		} {
+			if !strings.Contains(b.String(), expected) {
+				t.Error("expected:", expected, "found:", b.String())
+			}
+		}
+	})
+
+	t.Run("presence", func(t *testing.T) {
+		doc := &gpx.Document{
+			Waypoints: []gpx.Waypoint{{
+				Point:        gopostgis.PointZMS{SRID: 4326, X: 1, Y: 2, Valid: true},
+				HasElevation: true,
+			}},
+			Routes: []gpx.Route{{
+				Points: []gopostgis.PointZMS{{SRID: 4326, X: 1, Y: 2, Valid: true}},
+			}},
+			Tracks: []gpx.Track{{
+				Segments: [][]gopostgis.PointZMS{{
+					{SRID: 4326, X: 1, Y: 2, M: 1714557600, Valid: true},
+					{SRID: 4326, X: 1, Y: 3, Valid: true},
+				}},
+				HasTime: true,
+			}},
+		}
+
+		var b bytes.Buffer
+		if e := gpx.Encode(&b, doc); e != nil {
+			t.Fatal(e)
+		}
+
+		for expected, count := range map[string]int{
+			`<ele>`:  1,
+			`<time>`: 1,
+		} {
+			if found := strings.Count(b.String(), expected); found != count {
+				t.Error("expected:", count, expected, "found:", found, b.String())
+			}
+		}
+
+		found, e := gpx.Decode(&b)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(found, doc) {
+			t.Error("expected:", doc, "found:", found)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, p := range []gopostgis.PointZMS{
+			{SRID: 4326},
+			{SRID: 4326, Empty: true, Valid: true},
+			{SRID: 3857, Valid: true},
+		} {
+			doc := &gpx.Document{Waypoints: []gpx.Waypoint{{Point: p}}}
+			if e := gpx.Encode(&bytes.Buffer{}, doc); e == nil {
+				t.Error("expected error for:", p)
+			}
+		}
+
+		doc := &gpx.Document{Tracks: []gpx.Track{{
+			Segments: [][]gopostgis.PointZMS{{{SRID: 4326, X: math.NaN(), Valid: true}}},
+		}}}
+		if e := gpx.Encode(&bytes.Buffer{}, doc); !errors.Is(e, gopostgis.ErrNonFiniteCoordinate) {
+			t.Error("expected:", gopostgis.ErrNonFiniteCoordinate, "found:", e)
+		}
+	})
+}
+
+func TestDecodeErrors(t *testing.T) {
+	if _, e := gpx.Decode(strings.NewReader(`<gpx><wpt lat="1" lon="2"><time>yesterday</time></wpt></gpx>`)); e == nil {
+		t.Error("invalid time should fail")
+	}
+	if _, e := gpx.Decode(strings.NewReader(`<gpx><wpt lat="x" lon="2"></wpt></gpx>`)); e == nil {
+		t.Error("invalid latitude should fail")
+	}
+}
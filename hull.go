@@ -0,0 +1,390 @@
+package gopostgis
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Hulls of geometries, same as ST_ConvexHull and ST_ConcaveHull. The
+// geometry is any point type or a slice, possibly nested, of point type T.
+// NULL and EMPTY points are skipped. Hulls are polygons with a clockwise
+// exterior ring in the SRID of the input, without Z and M. A hull having
+// no area, of a single point or of collinear points, is nil.
+
+// ConvexHull returns the smallest convex polygon containing g.
+func ConvexHull[T PointType, P interface {
+	*T
+	setData(pointData)
+}](g any) ([][]T, error) {
+	xy, srid, err := hullPoints[T](g)
+	if err != nil {
+		return nil, err
+	}
+
+	hull := convexHull(xy)
+	if len(hull) < 3 {
+		return nil, nil
+	}
+
+	return toPolygon[T, P](hull, nil, srid), nil
+}
+
+// ConcaveHull returns a polygon containing g by eroding the Delaunay
+// triangulation of its points from the outside. The ratio is in [0, 1],
+// it is the fraction of the range of the triangulation edge lengths above
+// which border edges are eroded, 1 gives the convex hull. The result is
+// a single polygon without holes.
+func ConcaveHull[T PointType, P interface {
+	*T
+	setData(pointData)
+}](g any, ratio float64) ([][]T, error) {
+	if !(ratio >= 0 && ratio <= 1) {
+		return nil, fmt.Errorf("concave hull ratio must be in [0, 1]. got: %v", ratio)
+	}
+
+	xy, srid, err := hullPoints[T](g)
+	if err != nil {
+		return nil, err
+	}
+
+	if ratio == 1 {
+		if hull := convexHull(xy); len(hull) >= 3 {
+			return toPolygon[T, P](hull, nil, srid), nil
+		}
+		return nil, nil
+	}
+
+	hull := concaveHull(delaunay(xy), xy, ratio)
+	if len(hull) < 3 {
+		return nil, nil
+	}
+
+	return toPolygon[T, P](hull, nil, srid), nil
+}
+
+// Distinct planar coordinates of the points of g and their SRID.
+func hullPoints[T PointType](g any) ([][2]float64, uint32, error) {
+	var points []T
+	if err := collectPoints(reflect.ValueOf(g), &points); err != nil {
+		return nil, 0, err
+	}
+
+	var srid uint32
+	seen := map[[2]float64]bool{}
+	var xy [][2]float64
+	for i, p := range points {
+		d := p.data()
+		if i == 0 {
+			srid = d.srid
+		} else if d.srid != srid {
+			return nil, 0, fmt.Errorf("mixed srid. got: %d and %d", d.srid, srid)
+		}
+		if _, err := checkFinite(d.values()...); err != nil {
+			return nil, 0, err
+		}
+
+		c := [2]float64{d.coords[0], d.coords[1]}
+		if !seen[c] {
+			seen[c] = true
+			xy = append(xy, c)
+		}
+	}
+
+	return xy, srid, nil
+}
+
+// Appends the valid, non empty points of v to points.
+func collectPoints[T PointType](v reflect.Value, points *[]T) error {
+	if !v.IsValid() {
+		return fmt.Errorf("nil geometry")
+	}
+
+	if p, ok := v.Interface().(T); ok {
+		if d := p.data(); d.valid && !d.empty {
+			*points = append(*points, p)
+		}
+		return nil
+	}
+
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("geometry type not supported. got: %v", v.Type())
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := collectPoints(v.Index(i), points); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Counter clockwise convex hull of distinct points, without collinear
+// points. reference - Andrew's monotone chain.
+func convexHull(xy [][2]float64) [][2]float64 {
+	points := make([][2]float64, len(xy))
+	copy(points, xy)
+	sort.Slice(points, func(i, j int) bool {
+		if points[i][0] != points[j][0] {
+			return points[i][0] < points[j][0]
+		}
+		return points[i][1] < points[j][1]
+	})
+
+	if len(points) < 3 {
+		return points
+	}
+
+	hull := make([][2]float64, 0, 2*len(points))
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, c := range points {
+			for len(hull) >= start+2 && orientation(hull[len(hull)-2], hull[len(hull)-1], c) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, c)
+		}
+		// the last point starts the other chain
+		hull = hull[:len(hull)-1]
+
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+
+	return hull
+}
+
+// Triangle of the Delaunay triangulation, counter clockwise vertex indices
+// and the neighbors across edges v[i] -> v[i+1], -1 if there is none.
+type hullTriangle struct {
+	v        [3]int
+	adjacent [3]int
+	removed  bool
+}
+
+// Delaunay triangulation of distinct points by Bowyer-Watson.
+func delaunay(xy [][2]float64) []hullTriangle {
+	n := len(xy)
+	if n < 3 {
+		return nil
+	}
+
+	env := Envelope{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	for _, c := range xy {
+		env.add(c[0], c[1])
+	}
+	span := math.Max(math.Max(env.MaxX-env.MinX, env.MaxY-env.MinY), 1)
+	cx, cy := (env.MinX+env.MaxX)/2, (env.MinY+env.MaxY)/2
+
+	// super triangle around every point
+	points := append(xy[:n:n],
+		[2]float64{cx - 100*span, cy - 100*span},
+		[2]float64{cx + 100*span, cy - 100*span},
+		[2]float64{cx, cy + 100*span},
+	)
+
+	type circle struct {
+		v      [3]int
+		center [2]float64
+		r2     float64
+	}
+	makeCircle := func(a, b, c int) circle {
+		pa, pb, pc := points[a], points[b], points[c]
+		bx, by := pb[0]-pa[0], pb[1]-pa[1]
+		qx, qy := pc[0]-pa[0], pc[1]-pa[1]
+		d := 2 * (bx*qy - by*qx)
+		ux := (qy*(bx*bx+by*by) - by*(qx*qx+qy*qy)) / d
+		uy := (bx*(qx*qx+qy*qy) - qx*(bx*bx+by*by)) / d
+		return circle{v: [3]int{a, b, c}, center: [2]float64{pa[0] + ux, pa[1] + uy}, r2: ux*ux + uy*uy}
+	}
+
+	triangles := []circle{makeCircle(n, n+1, n+2)}
+	for i := 0; i < n; i++ {
+		p := points[i]
+
+		// edges of the cavity of triangles whose circumcircle contains p
+		edges := map[[2]int]bool{}
+		kept := triangles[:0]
+		for _, t := range triangles {
+			dx, dy := p[0]-t.center[0], p[1]-t.center[1]
+			if dx*dx+dy*dy >= t.r2 {
+				kept = append(kept, t)
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				a, b := t.v[k], t.v[(k+1)%3]
+				if edges[[2]int{b, a}] {
+					delete(edges, [2]int{b, a})
+				} else {
+					edges[[2]int{a, b}] = true
+				}
+			}
+		}
+
+		triangles = kept
+		for e := range edges {
+			if orientation(points[e[0]], points[e[1]], p) > 0 {
+				triangles = append(triangles, makeCircle(e[0], e[1], i))
+			}
+		}
+	}
+
+	var result []hullTriangle
+	for _, t := range triangles {
+		if t.v[0] < n && t.v[1] < n && t.v[2] < n {
+			result = append(result, hullTriangle{v: t.v, adjacent: [3]int{-1, -1, -1}})
+		}
+	}
+
+	edges := map[[2]int][2]int{}
+	for i, t := range result {
+		for k := 0; k < 3; k++ {
+			edges[[2]int{t.v[k], t.v[(k+1)%3]}] = [2]int{i, k}
+		}
+	}
+	for i := range result {
+		t := &result[i]
+		for k := 0; k < 3; k++ {
+			if o, ok := edges[[2]int{t.v[(k+1)%3], t.v[k]}]; ok {
+				t.adjacent[k] = o[0]
+			}
+		}
+	}
+
+	return result
+}
+
+// Border triangle queued by the length of its border edge.
+type hullItem struct {
+	index  int
+	length float64
+}
+
+// Longest border edge first.
+type hullQueue []hullItem
+
+func (q hullQueue) Len() int            { return len(q) }
+func (q hullQueue) Less(i, j int) bool  { return q[i].length > q[j].length }
+func (q hullQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *hullQueue) Push(x interface{}) { *q = append(*q, x.(hullItem)) }
+func (q *hullQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// Erodes border triangles having a border edge longer than the length at
+// ratio, keeping the triangulation connected and without holes. Returns
+// the counter clockwise boundary. reference - GEOS ConcaveHull.
+func concaveHull(triangles []hullTriangle, xy [][2]float64, ratio float64) [][2]float64 {
+	if len(triangles) == 0 {
+		return nil
+	}
+
+	length := func(a, b int) float64 {
+		return math.Hypot(xy[b][0]-xy[a][0], xy[b][1]-xy[a][1])
+	}
+
+	minLen, maxLen := math.Inf(1), 0.0
+	for _, t := range triangles {
+		for k := 0; k < 3; k++ {
+			l := length(t.v[k], t.v[(k+1)%3])
+			minLen = math.Min(minLen, l)
+			maxLen = math.Max(maxLen, l)
+		}
+	}
+	threshold := minLen + ratio*(maxLen-minLen)
+
+	border := map[int]bool{}
+	for _, t := range triangles {
+		for k := 0; k < 3; k++ {
+			if t.adjacent[k] < 0 {
+				border[t.v[k]], border[t.v[(k+1)%3]] = true, true
+			}
+		}
+	}
+
+	// single border edge of t, or -1
+	borderEdge := func(t *hullTriangle) int {
+		edge := -1
+		for k := 0; k < 3; k++ {
+			if t.adjacent[k] < 0 {
+				if edge >= 0 {
+					return -1
+				}
+				edge = k
+			}
+		}
+		return edge
+	}
+
+	q := &hullQueue{}
+	enqueue := func(i int) {
+		t := &triangles[i]
+		if k := borderEdge(t); k >= 0 {
+			if l := length(t.v[k], t.v[(k+1)%3]); l > threshold {
+				heap.Push(q, hullItem{index: i, length: l})
+			}
+		}
+	}
+	for i := range triangles {
+		enqueue(i)
+	}
+
+	for q.Len() > 0 {
+		i := heap.Pop(q).(hullItem).index
+		t := &triangles[i]
+		k := borderEdge(t)
+		if t.removed || k < 0 {
+			continue
+		}
+
+		// removing it would disconnect the triangulation
+		opposite := t.v[(k+2)%3]
+		if border[opposite] {
+			continue
+		}
+
+		t.removed = true
+		border[opposite] = true
+		for _, j := range t.adjacent {
+			if j < 0 {
+				continue
+			}
+			o := &triangles[j]
+			for m := 0; m < 3; m++ {
+				if o.adjacent[m] == i {
+					o.adjacent[m] = -1
+				}
+			}
+			enqueue(j)
+		}
+	}
+
+	// link the border edges into a ring
+	next := map[int]int{}
+	start := -1
+	for _, t := range triangles {
+		if t.removed {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			if t.adjacent[k] < 0 {
+				next[t.v[k]] = t.v[(k+1)%3]
+				start = t.v[k]
+			}
+		}
+	}
+
+	ring := [][2]float64{xy[start]}
+	for v := next[start]; v != start && len(ring) <= len(next); v = next[v] {
+		ring = append(ring, xy[v])
+	}
+
+	return ring
+}
@@ -0,0 +1,167 @@
+package gopostgis_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestConvexHull(t *testing.T) {
+	t.Run("points", func(t *testing.T) {
+		points := xyLine(0, 0, 2, 1, 4, 0, 3, 2, 4, 4, 2, 4, 0, 4, 1, 1, 2, 0)
+
+		found, err := gopostgis.ConvexHull[gopostgis.Point](points)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		expected := xyLine(0, 0, 0, 4, 4, 4, 4, 0, 0, 0)
+		if len(found) != 1 || len(found[0]) != len(expected) {
+			t.Fatal("expected:", expected, "found:", found)
+		}
+		for _, p := range expected {
+			if !hasVertex(found[0], p.X, p.Y) {
+				t.Error("expected:", expected, "found:", found)
+			}
+		}
+		if a := ringArea(found[0]); a != -16 {
+			t.Error("expected:", -16, "found:", a)
+		}
+	})
+
+	t.Run("any geometry", func(t *testing.T) {
+		polygons := [][][]gopostgis.PointS{
+			{{{SRID: 3857, X: 0, Y: 0, Valid: true}, {SRID: 3857, X: 1, Y: 0, Valid: true}, {SRID: 3857, X: 0, Y: 1, Valid: true}}},
+			{{{SRID: 3857, X: 5, Y: 5, Valid: true}, {SRID: 3857, Valid: false}, {SRID: 3857, Empty: true, Valid: true}}},
+		}
+		found, err := gopostgis.ConvexHull[gopostgis.PointS](polygons)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if len(found) != 1 || len(found[0]) != 5 || found[0][0].SRID != 3857 {
+			t.Error("expected: quadrilateral in srid 3857 found:", found)
+		}
+
+		single, err := gopostgis.ConvexHull[gopostgis.PointS](gopostgis.PointS{SRID: 3857, X: 1, Y: 1, Valid: true})
+		if err != nil || single != nil {
+			t.Error("expected:", nil, "found:", single, err)
+		}
+	})
+
+	t.Run("collinear", func(t *testing.T) {
+		found, err := gopostgis.ConvexHull[gopostgis.Point](xyLine(0, 0, 1, 1, 2, 2, 3, 3))
+		if err != nil || found != nil {
+			t.Error("expected:", nil, "found:", found, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.ConvexHull[gopostgis.Point]([]gopostgis.PointZ{{Valid: true}}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		mixed := []gopostgis.PointS{{SRID: 4326, Valid: true}, {SRID: 3857, Valid: true}}
+		if _, err := gopostgis.ConvexHull[gopostgis.PointS](mixed); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestConcaveHull(t *testing.T) {
+	// L shaped grid of points
+	var points []gopostgis.Point
+	for x := 0; x <= 4; x++ {
+		for y := 0; y <= 4; y++ {
+			if x < 3 || y < 3 {
+				points = append(points, gopostgis.Point{X: float64(x), Y: float64(y), Valid: true})
+			}
+		}
+	}
+
+	t.Run("ratio", func(t *testing.T) {
+		cases := []struct {
+			ratio float64
+			area  float64
+		}{
+			{0, 12},
+			{1, 14},
+		}
+		for _, c := range cases {
+			found, err := gopostgis.ConcaveHull[gopostgis.Point](points, c.ratio)
+			if err != nil {
+				t.Fatal("expected: nil found:", err)
+			}
+			if len(found) != 1 {
+				t.Fatal("expected: polygon found:", found)
+			}
+			if a := -ringArea(found[0]); a != c.area {
+				t.Error("expected:", c.area, "found:", a)
+			}
+		}
+	})
+
+	t.Run("contains every point", func(t *testing.T) {
+		r := rand.New(rand.NewSource(7))
+		var points []gopostgis.Point
+		for i := 0; i < 300; i++ {
+			// C shaped points, open to the east
+			a, d := 0.5+r.Float64()*(2*math.Pi-1), 5+r.Float64()
+			points = append(points, gopostgis.Point{X: d * math.Cos(a), Y: d * math.Sin(a), Valid: true})
+		}
+
+		convex, err := gopostgis.ConvexHull[gopostgis.Point](points)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		previous := -ringArea(convex[0])
+		for _, ratio := range []float64{0.8, 0.4, 0} {
+			found, err := gopostgis.ConcaveHull[gopostgis.Point](points, ratio)
+			if err != nil {
+				t.Fatal("expected: nil found:", err)
+			}
+			area := -ringArea(found[0])
+			if area <= 0 || area > previous+1e-9 {
+				t.Error("expected: shrinking area found:", area, "after", previous)
+			}
+			previous = area
+
+			for _, p := range points {
+				if !hasVertex(found[0], p.X, p.Y) && !ringCovers(found[0], p) {
+					t.Error("expected: covered point found:", p)
+					break
+				}
+			}
+		}
+
+		// area of the C shape the points are drawn from
+		if c := 5.5 * (2*math.Pi - 1); previous > c {
+			t.Error("expected: less than", c, "found:", previous)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.ConcaveHull[gopostgis.Point](points, 1.5); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.ConcaveHull[gopostgis.Point](points, math.NaN()); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+// Whether p is inside or on ring, by ray casting.
+func ringCovers(ring []gopostgis.Point, p gopostgis.Point) bool {
+	inside := false
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		cross := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+		if math.Abs(cross) < 1e-9 && p.X >= math.Min(a.X, b.X) && p.X <= math.Max(a.X, b.X) &&
+			p.Y >= math.Min(a.Y, b.Y) && p.Y <= math.Max(a.Y, b.Y) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,353 @@
+// Package kml converts KML 2.2 placemarks to and from go-postgis types.
+// reference - https://developers.google.com/kml/documentation/kmlreference
+//
+// Every KML coordinate becomes a [gopostgis.PointZMS] of SRID 4326, with
+// X as longitude, Y as latitude, Z as altitude and M as the TimeStamp of
+// the placemark in unix seconds. Placemarks tell whether their coordinates
+// have altitudes and whether they have a TimeStamp.
+package kml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// Placemark of a KML document.
+//
+// Geometry is one of
+//   - gopostgis.PointZMS for a Point
+//   - []gopostgis.PointZMS for a LineString
+//   - [][]gopostgis.PointZMS for a Polygon, the outer boundary followed
+//     by the inner boundaries
+type Placemark struct {
+	Name         string
+	Description  string
+	Geometry     any
+	HasElevation bool // Z of Geometry are altitudes
+	HasTime      bool // M of Geometry is the TimeStamp
+}
+
+// Document is the content of a KML file. Placemarks of nested folders
+// are flattened while decoding.
+type Document struct {
+	Name       string
+	Placemarks []Placemark
+}
+
+type kmlRing struct {
+	Coordinates string `xml:"LinearRing>coordinates"`
+}
+
+type kmlPolygon struct {
+	Outer kmlRing   `xml:"outerBoundaryIs"`
+	Inner []kmlRing `xml:"innerBoundaryIs"`
+}
+
+type kmlPlacemark struct {
+	Name          string      `xml:"name,omitempty"`
+	Description   string      `xml:"description,omitempty"`
+	When          string      `xml:"TimeStamp>when,omitempty"`
+	Point         *string     `xml:"Point>coordinates"`
+	LineString    *string     `xml:"LineString>coordinates"`
+	Polygon       *kmlPolygon `xml:"Polygon"`
+	MultiGeometry *struct{}   `xml:"MultiGeometry"`
+}
+
+type kmlContainer struct {
+	Name       string         `xml:"name,omitempty"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+	Folders    []kmlContainer `xml:"Folder"`
+	Documents  []kmlContainer `xml:"Document"`
+}
+
+type kmlFile struct {
+	XMLName xml.Name `xml:"kml"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	kmlContainer
+}
+
+// Decode reads a KML document from r.
+func Decode(r io.Reader) (*Document, error) {
+	var f kmlFile
+	if err := xml.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+
+	var doc Document
+	if err := decodeContainer(&doc, f.kmlContainer); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+func decodeContainer(doc *Document, c kmlContainer) error {
+	if doc.Name == "" {
+		doc.Name = c.Name
+	}
+
+	for _, p := range c.Placemarks {
+		placemark, err := decodePlacemark(p)
+		if err != nil {
+			return fmt.Errorf("placemark %q: %w", p.Name, err)
+		}
+		doc.Placemarks = append(doc.Placemarks, placemark)
+	}
+
+	for _, d := range c.Documents {
+		if err := decodeContainer(doc, d); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range c.Folders {
+		if err := decodeContainer(doc, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodePlacemark(p kmlPlacemark) (Placemark, error) {
+	placemark := Placemark{
+		Name:        p.Name,
+		Description: p.Description,
+		HasTime:     p.When != "",
+	}
+
+	var m float64
+	if p.When != "" {
+		t, err := parseWhen(p.When)
+		if err != nil {
+			return placemark, fmt.Errorf("invalid kml time: %w", err)
+		}
+		m = float64(t.Unix()) + float64(t.Nanosecond())/1e9
+	}
+
+	switch {
+	case p.Point != nil:
+		points, err := decodeCoordinates(*p.Point, m)
+		if err != nil {
+			return placemark, err
+		}
+		if len(points) != 1 {
+			return placemark, fmt.Errorf("kml point must have 1 coordinate. got: %d", len(points))
+		}
+		placemark.Geometry = points[0]
+		placemark.HasElevation = hasAltitude(*p.Point)
+
+	case p.LineString != nil:
+		points, err := decodeCoordinates(*p.LineString, m)
+		if err != nil {
+			return placemark, err
+		}
+		placemark.Geometry = points
+		placemark.HasElevation = hasAltitude(*p.LineString)
+
+	case p.Polygon != nil:
+		rings := make([][]gopostgis.PointZMS, 0, 1+len(p.Polygon.Inner))
+		for _, r := range append([]kmlRing{p.Polygon.Outer}, p.Polygon.Inner...) {
+			points, err := decodeCoordinates(r.Coordinates, m)
+			if err != nil {
+				return placemark, err
+			}
+			rings = append(rings, points)
+			placemark.HasElevation = placemark.HasElevation || hasAltitude(r.Coordinates)
+		}
+		placemark.Geometry = rings
+
+	case p.MultiGeometry != nil:
+		return placemark, fmt.Errorf("kml MultiGeometry not supported")
+	}
+
+	return placemark, nil
+}
+
+// Parses a KML when, which is a dateTime, date, gYearMonth or gYear.
+func parseWhen(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if err == nil {
+			break
+		}
+		if v, e := time.Parse(layout, s); e == nil {
+			t, err = v, nil
+		}
+	}
+
+	return t, err
+}
+
+// Tells whether any tuple of coordinates s has an altitude.
+func hasAltitude(s string) bool {
+	for _, tuple := range strings.Fields(s) {
+		if strings.Count(tuple, ",") == 2 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parses "lon,lat[,alt] lon,lat[,alt] ..." tuples.
+func decodeCoordinates(s string, m float64) ([]gopostgis.PointZMS, error) {
+	tuples := strings.Fields(s)
+	points := make([]gopostgis.PointZMS, 0, len(tuples))
+
+	for _, tuple := range tuples {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid kml coordinate. got: %q", tuple)
+		}
+
+		var values [3]float64
+		for i, part := range parts {
+			v, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid kml coordinate. got: %q", tuple)
+			}
+			values[i] = v
+		}
+
+		points = append(points, gopostgis.PointZMS{
+			SRID:  4326,
+			X:     values[0],
+			Y:     values[1],
+			Z:     values[2],
+			M:     m,
+			Valid: true,
+		})
+	}
+
+	return points, nil
+}
+
+// Encode writes doc to w as a KML document.
+func Encode(w io.Writer, doc *Document) error {
+	f := kmlFile{
+		Xmlns: "http://www.opengis.net/kml/2.2",
+	}
+
+	d := kmlContainer{Name: doc.Name}
+	for i, p := range doc.Placemarks {
+		placemark, err := encodePlacemark(p)
+		if err != nil {
+			return fmt.Errorf("placemark %d: %w", i, err)
+		}
+		d.Placemarks = append(d.Placemarks, placemark)
+	}
+	f.Documents = []kmlContainer{d}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(f); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func encodePlacemark(p Placemark) (kmlPlacemark, error) {
+	placemark := kmlPlacemark{
+		Name:        p.Name,
+		Description: p.Description,
+	}
+
+	var m float64
+	var err error
+
+	switch g := p.Geometry.(type) {
+	case nil:
+
+	case gopostgis.PointZMS:
+		var s string
+		if s, m, err = encodeCoordinates([]gopostgis.PointZMS{g}, p.HasElevation); err != nil {
+			return placemark, err
+		}
+		placemark.Point = &s
+
+	case []gopostgis.PointZMS:
+		var s string
+		if s, m, err = encodeCoordinates(g, p.HasElevation); err != nil {
+			return placemark, err
+		}
+		placemark.LineString = &s
+
+	case [][]gopostgis.PointZMS:
+		if len(g) == 0 {
+			return placemark, fmt.Errorf("kml polygon must have an outer boundary")
+		}
+		polygon := kmlPolygon{}
+		for i, r := range g {
+			s, rm, err := encodeCoordinates(r, p.HasElevation)
+			if err != nil {
+				return placemark, err
+			}
+			if i == 0 {
+				m = rm
+				polygon.Outer.Coordinates = s
+			} else {
+				polygon.Inner = append(polygon.Inner, kmlRing{Coordinates: s})
+			}
+		}
+		placemark.Polygon = &polygon
+
+	default:
+		return placemark, fmt.Errorf("geometry type not supported. got: %T", p.Geometry)
+	}
+
+	if p.HasTime && m != 0 {
+		ms := int64(math.Round(m * 1e3))
+		placemark.When = time.UnixMilli(ms).UTC().Format(time.RFC3339Nano)
+	}
+
+	return placemark, nil
+}
+
+// Formats points as KML coordinates, with altitudes if altitude is set.
+// Also returns the M of the first point.
+func encodeCoordinates(points []gopostgis.PointZMS, altitude bool) (string, float64, error) {
+	var b strings.Builder
+	for i, p := range points {
+		if !p.Valid || p.Empty {
+			return "", 0, fmt.Errorf("kml can not have NULL or EMPTY points")
+		}
+		if p.SRID != 4326 {
+			return "", 0, fmt.Errorf("kml srid must be 4326. got: %d", p.SRID)
+		}
+		if !p.IsValid() {
+			return "", 0, gopostgis.ErrNonFiniteCoordinate
+		}
+
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strconv.FormatFloat(p.X, 'f', -1, 64))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatFloat(p.Y, 'f', -1, 64))
+		if altitude {
+			b.WriteByte(',')
+			b.WriteString(strconv.FormatFloat(p.Z, 'f', -1, 64))
+		}
+	}
+
+	if len(points) == 0 {
+		return "", 0, nil
+	}
+
+	return b.String(), points[0].M, nil
+}
@@ -0,0 +1,207 @@
+package kml_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+	"github.com/asif-mahmud/go-postgis/kml"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Survey</name>
+    <Placemark>
+      <name>Well</name>
+      <description>Water well</description>
+      <TimeStamp><when>2024-05-01T10:00:00Z</when></TimeStamp>
+      <Point><coordinates>13.4,52.5,34.5</coordinates></Point>
+    </Placemark>
+    <Folder>
+      <name>Paths</name>
+      <Placemark>
+        <name>Path</name>
+        <LineString>
+          <coordinates>
+            13.4,52.5 13.5,52.6,10
+          </coordinates>
+        </LineString>
+      </Placemark>
+      <Placemark>
+        <name>Field</name>
+        <Polygon>
+          <outerBoundaryIs><LinearRing><coordinates>0,0 4,0 4,4 0,4 0,0</coordinates></LinearRing></outerBoundaryIs>
+          <innerBoundaryIs><LinearRing><coordinates>1,1 2,1 2,2 1,1</coordinates></LinearRing></innerBoundaryIs>
+        </Polygon>
+      </Placemark>
+    </Folder>
+  </Document>
+</kml>`
+
+func pt(x, y, z, m float64) gopostgis.PointZMS {
+	return gopostgis.PointZMS{SRID: 4326, X: x, Y: y, Z: z, M: m, Valid: true}
+}
+
+func TestDecode(t *testing.T) {
+	doc, e := kml.Decode(strings.NewReader(sample))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	expected := &kml.Document{
+		Name: "Survey",
+		Placemarks: []kml.Placemark{
+			{Name: "Well", Description: "Water well", Geometry: pt(13.4, 52.5, 34.5, 1714557600), HasElevation: true, HasTime: true},
+			{Name: "Path", Geometry: []gopostgis.PointZMS{pt(13.4, 52.5, 0, 0), pt(13.5, 52.6, 10, 0)}, HasElevation: true},
+			{Name: "Field", Geometry: [][]gopostgis.PointZMS{
+				{pt(0, 0, 0, 0), pt(4, 0, 0, 0), pt(4, 4, 0, 0), pt(0, 4, 0, 0), pt(0, 0, 0, 0)},
+				{pt(1, 1, 0, 0), pt(2, 1, 0, 0), pt(2, 2, 0, 0), pt(1, 1, 0, 0)},
+			}},
+		},
+	}
+
+	if !reflect.DeepEqual(doc, expected) {
+		t.Error("expected:", expected, "found:", doc)
+	}
+}
+
+func TestDecodeWhen(t *testing.T) {
+	for when, expected := range map[string]float64{
+		"2024-05-01T10:00:00+02:00": 1714550400,
+		"2024-05-01":                1714521600,
+		"2024-05":                   1714521600,
+		"2024":                      1704067200,
+	} {
+		s := `<kml><Placemark><TimeStamp><when>` + when + `</when></TimeStamp><Point><coordinates>1,2</coordinates></Point></Placemark></kml>`
+		doc, e := kml.Decode(strings.NewReader(s))
+		if e != nil {
+			t.Error(when, e)
+			continue
+		}
+		if p := doc.Placemarks[0]; !p.HasTime || p.Geometry.(gopostgis.PointZMS).M != expected {
+			t.Error("expected:", expected, "found:", p)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, s := range []string{
+		`<kml><Placemark><Point><coordinates>1</coordinates></Point></Placemark></kml>`,
+		`<kml><Placemark><Point><coordinates>1,a</coordinates></Point></Placemark></kml>`,
+		`<kml><Placemark><Point><coordinates>1,2 3,4</coordinates></Point></Placemark></kml>`,
+		`<kml><Placemark><TimeStamp><when>now</when></TimeStamp></Placemark></kml>`,
+		`<kml><Placemark><MultiGeometry></MultiGeometry></Placemark></kml>`,
+	} {
+		if _, e := kml.Decode(strings.NewReader(s)); e == nil {
+			t.Error("expected error for:", s)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		doc, e := kml.Decode(strings.NewReader(sample))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		var b bytes.Buffer
+		if e := kml.Encode(&b, doc); e != nil {
+			t.Fatal(e)
+		}
+
+		found, e := kml.Decode(&b)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(found, doc) {
+			t.Error("expected:", doc, "found:", found)
+		}
+	})
+
+	t.Run("output", func(t *testing.T) {
+		doc := &kml.Document{
+			Placemarks: []kml.Placemark{
+				{Name: "A", Geometry: pt(1.5, 2, 3, 1714557600.25), HasElevation: true, HasTime: true},
+			},
+		}
+
+		var b bytes.Buffer
+		if e := kml.Encode(&b, doc); e != nil {
+			t.Fatal(e)
+		}
+
+		for _, expected := range []string{
+			`<kml xmlns="http://www.opengis.net/kml/2.2">`,
+			`<when>2024-05-01T10:00:00.25Z</when>`,
+			`<coordinates>1.5,2,3</coordinates>`,
+		} {
+			if !strings.Contains(b.String(), expected) {
+				t.Error("expected:", expected, "found:", b.String())
+			}
+		}
+	})
+
+	t.Run("presence", func(t *testing.T) {
+		doc := &kml.Document{
+			Placemarks: []kml.Placemark{
+				{Name: "A", Geometry: pt(1, 2, 0, 1714557600), HasElevation: true, HasTime: true},
+				{Name: "B", Geometry: pt(1, 2, 0, 0)},
+			},
+		}
+
+		var b bytes.Buffer
+		if e := kml.Encode(&b, doc); e != nil {
+			t.Fatal(e)
+		}
+
+		for expected, count := range map[string]int{
+			`<coordinates>1,2,0</coordinates>`: 1,
+			`<coordinates>1,2</coordinates>`:   1,
+			`<when>`:                           1,
+		} {
+			if found := strings.Count(b.String(), expected); found != count {
+				t.Error("expected:", count, expected, "found:", found, b.String())
+			}
+		}
+
+		found, e := kml.Decode(&b)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(found, doc) {
+			t.Error("expected:", doc, "found:", found)
+		}
+
+		// no TimeStamp is written for a zero M
+		doc.Placemarks[1].HasTime = true
+		b.Reset()
+		if e := kml.Encode(&b, doc); e != nil || strings.Count(b.String(), `<when>`) != 1 {
+			t.Error("expected: 1 <when> found:", b.String(), e)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, g := range []any{
+			gopostgis.PointZMS{SRID: 4326},
+			gopostgis.PointZMS{SRID: 3857, Valid: true},
+			[][]gopostgis.PointZMS{},
+			gopostgis.Point{Valid: true},
+		} {
+			doc := &kml.Document{Placemarks: []kml.Placemark{{Geometry: g}}}
+			if e := kml.Encode(&bytes.Buffer{}, doc); e == nil {
+				t.Error("expected error for:", g)
+			}
+		}
+
+		doc := &kml.Document{Placemarks: []kml.Placemark{{Geometry: pt(math.Inf(1), 0, 0, 0)}}}
+		if e := kml.Encode(&bytes.Buffer{}, doc); !errors.Is(e, gopostgis.ErrNonFiniteCoordinate) {
+			t.Error("expected:", gopostgis.ErrNonFiniteCoordinate, "found:", e)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+)
+
+// DistanceMetric selects how [RTree.KNN] measures distances.
+type DistanceMetric int
+
+const (
+	// PlanarDistance is the euclidean distance in the units of the
+	// coordinates, same as the <-> operator on geometry.
+	PlanarDistance DistanceMetric = iota
+
+	// GeodesicDistance is the great circle distance in meters on the mean
+	// earth sphere, same as the <-> operator on geography. Coordinates must
+	// be longitude/latitude degrees of a geographic SRID.
+	GeodesicDistance
+)
+
+// Mean earth radius in meters, used by postgis for sphere calculations.
+const earthRadius = 6371008.7714
+
+// Neighbor is a value found by [RTree.KNN] along with its distance.
+type Neighbor[T any] struct {
+	Value    T
+	Distance float64
+}
+
+// KNN returns at most k values of t closest to query, ordered by distance
+// like ORDER BY geom <-> query LIMIT k. Point values are measured exactly,
+// other values by the distance to their envelope. Point values having an
+// SRID other than the SRID of query are an error, same as postgis.
+func (t *RTree[T]) KNN(query PointS, k int, metric DistanceMetric) ([]Neighbor[T], error) {
+	if !query.Valid || query.Empty {
+		return nil, fmt.Errorf("knn query can not be NULL or EMPTY")
+	}
+
+	if !query.IsValid() {
+		return nil, ErrNonFiniteCoordinate
+	}
+
+	var dist func(Envelope) float64
+	switch metric {
+	case PlanarDistance:
+		dist = func(e Envelope) float64 {
+			return e.Distance(query.X, query.Y)
+		}
+
+	case GeodesicDistance:
+		if info, ok := LookupSRID(query.SRID); !ok || !info.Geographic {
+			return nil, fmt.Errorf("geodesic distance needs a geographic srid. got: %d", query.SRID)
+		}
+		cosLat := math.Cos(query.Y * math.Pi / 180)
+		dist = func(e Envelope) float64 {
+			return geodesicBoxDistance(query.X, query.Y, cosLat, e)
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid distance metric. got: %d", metric)
+	}
+
+	var neighbors []Neighbor[T]
+	var err error
+	t.nearest(k, dist, func(v T, d float64) {
+		if p, ok := any(v).(interface{ data() pointData }); ok && err == nil {
+			if data := p.data(); data.layout.srid && data.srid != query.SRID {
+				err = fmt.Errorf("mixed srid. got: %d and %d", data.srid, query.SRID)
+			}
+		}
+		neighbors = append(neighbors, Neighbor[T]{Value: v, Distance: d})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return neighbors, nil
+}
+
+// Great circle distance in meters from lng, lat to the nearest point of
+// e, both in degrees. It is exact for points and never overestimates for
+// boxes, as needed by the best first search.
+// reference - https://github.com/mourner/geokdbush
+func geodesicBoxDistance(lng, lat, cosLat float64, e Envelope) float64 {
+	const rad = math.Pi / 180
+
+	var h float64
+	switch {
+	case lng >= e.MinX && lng <= e.MaxX:
+		switch {
+		case lat < e.MinY:
+			h = haversin((lat - e.MinY) * rad)
+		case lat > e.MaxY:
+			h = haversin((lat - e.MaxY) * rad)
+		}
+
+	default:
+		// distance to the closest meridian of the box peaks at the vertex
+		// of the great circle, if it lies within the latitudes of the box
+		hdLng := math.Min(haversin((lng-e.MinX)*rad), haversin((lng-e.MaxX)*rad))
+		vertex := vertexLatitude(lat, hdLng)
+		if vertex > e.MinY && vertex < e.MaxY {
+			h = haversinDistance(hdLng, cosLat, lat, vertex)
+		} else {
+			h = math.Min(haversinDistance(hdLng, cosLat, lat, e.MinY), haversinDistance(hdLng, cosLat, lat, e.MaxY))
+		}
+	}
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+func haversin(theta float64) float64 {
+	s := math.Sin(theta / 2)
+	return s * s
+}
+
+// Haversine of the angle between lat1 and lat2 which are hdLng apart.
+func haversinDistance(hdLng, cosLat1, lat1, lat2 float64) float64 {
+	const rad = math.Pi / 180
+	return cosLat1*math.Cos(lat2*rad)*hdLng + haversin((lat1-lat2)*rad)
+}
+
+func vertexLatitude(lat, hdLng float64) float64 {
+	cosDLng := 1 - 2*hdLng
+	if cosDLng <= 0 {
+		if lat > 0 {
+			return 90
+		}
+		return -90
+	}
+
+	return math.Atan(math.Tan(lat*math.Pi/180)/cosDLng) * 180 / math.Pi
+}
@@ -0,0 +1,89 @@
+package gopostgis_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func haversine(a, b gopostgis.PointS) float64 {
+	const rad = math.Pi / 180
+	dLat := (b.Y - a.Y) * rad
+	dLng := (b.X - a.X) * rad
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(a.Y*rad)*math.Cos(b.Y*rad)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * 6371008.7714 * math.Asin(math.Sqrt(h))
+}
+
+func TestKNN(t *testing.T) {
+	points := randomPoints(5000, 2)
+	tree, e := gopostgis.NewRTree(points...)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	check := func(t *testing.T, query gopostgis.PointS, metric gopostgis.DistanceMetric, dist func(a, b gopostgis.PointS) float64) {
+		expected := append([]gopostgis.PointS{}, points...)
+		sort.Slice(expected, func(i, j int) bool {
+			return dist(query, expected[i]) < dist(query, expected[j])
+		})
+
+		found, e := tree.KNN(query, 20, metric)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(found) != 20 {
+			t.Fatal("expected: 20 found:", len(found))
+		}
+		for i, n := range found {
+			if n.Value != expected[i] || math.Abs(n.Distance-dist(query, expected[i])) > 1e-6 {
+				t.Error("expected:", expected[i], dist(query, expected[i]), "found:", n.Value, n.Distance)
+			}
+		}
+	}
+
+	t.Run("planar", func(t *testing.T) {
+		check(t, gopostgis.PointS{SRID: 4326, X: 90.4, Y: 23.8, Valid: true}, gopostgis.PlanarDistance,
+			func(a, b gopostgis.PointS) float64 { return math.Hypot(a.X-b.X, a.Y-b.Y) })
+	})
+
+	t.Run("geodesic", func(t *testing.T) {
+		for _, q := range []gopostgis.PointS{
+			{SRID: 4326, X: 90.4, Y: 23.8, Valid: true},
+			// close to the pole and the antimeridian
+			{SRID: 4326, X: 179.5, Y: 85, Valid: true},
+			{SRID: 4326, X: -179.9, Y: -60, Valid: true},
+		} {
+			check(t, q, gopostgis.GeodesicDistance, haversine)
+		}
+	})
+
+	t.Run("geodesic distance", func(t *testing.T) {
+		paris := gopostgis.PointS{SRID: 4326, X: 2.3522, Y: 48.8566, Valid: true}
+		cities, _ := gopostgis.NewRTree(paris)
+		found, e := cities.KNN(gopostgis.PointS{SRID: 4326, X: -0.1276, Y: 51.5072, Valid: true}, 1, gopostgis.GeodesicDistance)
+		if e != nil {
+			t.Fatal(e)
+		}
+		// ST_Distance('POINT(-0.1276 51.5072)'::geography, 'POINT(2.3522 48.8566)'::geography, false)
+		if len(found) != 1 || math.Abs(found[0].Distance-343530.34) > 0.01 {
+			t.Error("unexpected result:", found)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, e := tree.KNN(gopostgis.PointS{SRID: 4326}, 1, gopostgis.PlanarDistance); e == nil {
+			t.Error("NULL query should fail")
+		}
+		if _, e := tree.KNN(gopostgis.PointS{SRID: 3857, Valid: true}, 1, gopostgis.GeodesicDistance); e == nil {
+			t.Error("geodesic distance in srid 3857 should fail")
+		}
+		if _, e := tree.KNN(gopostgis.PointS{SRID: 3857, Valid: true}, 1, gopostgis.PlanarDistance); e == nil {
+			t.Error("mixed srid should fail")
+		}
+		if _, e := tree.KNN(gopostgis.PointS{SRID: 4326, Valid: true}, 1, gopostgis.DistanceMetric(9)); e == nil {
+			t.Error("invalid metric should fail")
+		}
+	})
+}
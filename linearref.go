@@ -0,0 +1,210 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+)
+
+// Linear referencing over lines, same as the postgis functions of the same
+// names. Positions along a line are fractions of its 2D length. Z and M of
+// new points are interpolated linearly between the vertices around them,
+// so measured lines give measured points.
+
+// LineInterpolatePoint returns the point at fraction of the length of line.
+func LineInterpolatePoint[T PointType, P interface {
+	*T
+	setData(pointData)
+}](line []T, fraction float64) (T, error) {
+	var zero T
+	cum, err := lineLengths(line)
+	if err != nil {
+		return zero, err
+	}
+
+	if !(fraction >= 0 && fraction <= 1) {
+		return zero, fmt.Errorf("line fraction must be in [0, 1]. got: %v", fraction)
+	}
+
+	return interpolateAt[T, P](line, cum, fraction), nil
+}
+
+// LineLocatePoint returns the fraction of the length of line at the point
+// of line closest to p.
+func LineLocatePoint[T PointType, Q PointType](line []T, p Q) (float64, error) {
+	cum, err := lineLengths(line)
+	if err != nil {
+		return 0, err
+	}
+
+	d := p.data()
+	if !d.valid || d.empty {
+		return 0, fmt.Errorf("locate of NULL or EMPTY point")
+	}
+	if l := line[0].data(); l.layout.srid && d.layout.srid && l.srid != d.srid {
+		return 0, fmt.Errorf("mixed srid. got: %d and %d", d.srid, l.srid)
+	}
+
+	total := cum[len(cum)-1]
+	if total == 0 {
+		return 0, nil
+	}
+
+	c := [2]float64{d.coords[0], d.coords[1]}
+	best, at := math.Inf(1), 0.0
+	for i := 1; i < len(line); i++ {
+		a, b := line[i-1].data(), line[i].data()
+		t := segmentParam(c, [2]float64{a.coords[0], a.coords[1]}, [2]float64{b.coords[0], b.coords[1]})
+		x := a.coords[0] + t*(b.coords[0]-a.coords[0])
+		y := a.coords[1] + t*(b.coords[1]-a.coords[1])
+		if dist := math.Hypot(c[0]-x, c[1]-y); dist < best {
+			best = dist
+			at = cum[i-1] + t*(cum[i]-cum[i-1])
+		}
+	}
+
+	return at / total, nil
+}
+
+// LineSubstring returns the part of line between fractions start and end
+// of its length. The result is a single point when start equals end.
+func LineSubstring[T PointType, P interface {
+	*T
+	setData(pointData)
+}](line []T, start, end float64) ([]T, error) {
+	cum, err := lineLengths(line)
+	if err != nil {
+		return nil, err
+	}
+
+	if !(start >= 0 && start <= end && end <= 1) {
+		return nil, fmt.Errorf("line substring needs 0 <= start <= end <= 1. got: %v and %v", start, end)
+	}
+
+	first := interpolateAt[T, P](line, cum, start)
+	if start == end {
+		return []T{first}, nil
+	}
+
+	total := cum[len(cum)-1]
+	sub := []T{first}
+	for i, p := range line {
+		if cum[i] > start*total && cum[i] < end*total {
+			sub = append(sub, p)
+		}
+	}
+
+	return append(sub, interpolateAt[T, P](line, cum, end)), nil
+}
+
+// LocateAlong returns the points of line having measure, moved by offset
+// to the left of the line, or to the right for negative offsets. The
+// point type must have M.
+func LocateAlong[T PointType, P interface {
+	*T
+	setData(pointData)
+}](line []T, measure, offset float64) ([]T, error) {
+	if _, err := lineLengths(line); err != nil {
+		return nil, err
+	}
+
+	l := line[0].data().layout
+	if l.flags&ewkbMFlag == 0 {
+		return nil, fmt.Errorf("locate along needs measures. got: %v", l.names)
+	}
+	m := l.dims() - 1
+
+	var points []T
+	add := func(p T, a, b pointData) {
+		if offset != 0 {
+			dx, dy := b.coords[0]-a.coords[0], b.coords[1]-a.coords[1]
+			if n := math.Hypot(dx, dy); n > 0 {
+				d := p.data()
+				d.coords[0] -= offset * dy / n
+				d.coords[1] += offset * dx / n
+				P(&p).setData(d)
+			}
+		}
+
+		if k := len(points); k > 0 && points[k-1] == p {
+			return
+		}
+		points = append(points, p)
+	}
+
+	for i := 1; i < len(line); i++ {
+		a, b := line[i-1].data(), line[i].data()
+		m0, m1 := a.coords[m], b.coords[m]
+		if measure < math.Min(m0, m1) || measure > math.Max(m0, m1) {
+			continue
+		}
+
+		if m0 == m1 {
+			add(line[i-1], a, b)
+			add(line[i], a, b)
+			continue
+		}
+
+		add(interpolate[T, P](a, b, (measure-m0)/(m1-m0)), a, b)
+	}
+
+	return points, nil
+}
+
+// Cumulative 2D lengths of line at each vertex.
+func lineLengths[T PointType](line []T) ([]float64, error) {
+	if len(line) == 0 {
+		return nil, fmt.Errorf("empty line")
+	}
+
+	cum := make([]float64, len(line))
+	for i, p := range line {
+		d := p.data()
+		if !d.valid || d.empty {
+			return nil, fmt.Errorf("line with NULL or EMPTY point")
+		}
+		if _, err := checkFinite(d.values()...); err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			prev := line[i-1].data()
+			cum[i] = cum[i-1] + math.Hypot(d.coords[0]-prev.coords[0], d.coords[1]-prev.coords[1])
+		}
+	}
+
+	return cum, nil
+}
+
+// Point at fraction of the length of line with cumulative lengths cum.
+func interpolateAt[T PointType, P interface {
+	*T
+	setData(pointData)
+}](line []T, cum []float64, fraction float64) T {
+	total := cum[len(cum)-1]
+	at := fraction * total
+
+	for i := 1; i < len(line); i++ {
+		if at <= cum[i] {
+			seg := cum[i] - cum[i-1]
+			if seg == 0 {
+				return line[i-1]
+			}
+			return interpolate[T, P](line[i-1].data(), line[i].data(), (at-cum[i-1])/seg)
+		}
+	}
+
+	return line[len(line)-1]
+}
+
+// Point at t between a and b, every coordinate interpolated.
+func interpolate[T PointType, P interface {
+	*T
+	setData(pointData)
+}](a, b pointData, t float64) T {
+	for i := range a.values() {
+		a.coords[i] += t * (b.coords[i] - a.coords[i])
+	}
+
+	var p T
+	P(&p).setData(a)
+	return p
+}
@@ -0,0 +1,188 @@
+package gopostgis_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// Measured L shaped line of length 20, measures running from 100 to 300.
+var measuredLine = []gopostgis.PointZMS{
+	{SRID: 3857, X: 0, Y: 0, Z: 0, M: 100, Valid: true},
+	{SRID: 3857, X: 10, Y: 0, Z: 10, M: 200, Valid: true},
+	{SRID: 3857, X: 10, Y: 10, Z: 10, M: 300, Valid: true},
+}
+
+func TestLineInterpolatePoint(t *testing.T) {
+	cases := []struct {
+		fraction float64
+		expected gopostgis.PointZMS
+	}{
+		{0, measuredLine[0]},
+		{0.25, gopostgis.PointZMS{SRID: 3857, X: 5, Y: 0, Z: 5, M: 150, Valid: true}},
+		{0.5, measuredLine[1]},
+		{0.75, gopostgis.PointZMS{SRID: 3857, X: 10, Y: 5, Z: 10, M: 250, Valid: true}},
+		{1, measuredLine[2]},
+	}
+
+	for _, c := range cases {
+		found, err := gopostgis.LineInterpolatePoint(measuredLine, c.fraction)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if found != c.expected {
+			t.Error("expected:", c.expected, "found:", found)
+		}
+	}
+
+	t.Run("measured", func(t *testing.T) {
+		line := []gopostgis.PointM{{X: 0, Y: 0, M: 0, Valid: true}, {X: 0, Y: 4, M: 8, Valid: true}}
+		found, err := gopostgis.LineInterpolatePoint(line, 0.5)
+		expected := gopostgis.PointM{X: 0, Y: 2, M: 4, Valid: true}
+		if err != nil || found != expected {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.LineInterpolatePoint(measuredLine, 1.5); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.LineInterpolatePoint([]gopostgis.Point{}, 0.5); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.LineInterpolatePoint([]gopostgis.Point{{}}, 0.5); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestLineLocatePoint(t *testing.T) {
+	cases := []struct {
+		point    gopostgis.PointS
+		expected float64
+	}{
+		{gopostgis.PointS{SRID: 3857, X: -5, Y: -5, Valid: true}, 0},
+		{gopostgis.PointS{SRID: 3857, X: 5, Y: 3, Valid: true}, 0.25},
+		{gopostgis.PointS{SRID: 3857, X: 12, Y: 15, Valid: true}, 1},
+		{gopostgis.PointS{SRID: 3857, X: 7, Y: 5, Valid: true}, 0.75},
+	}
+
+	for _, c := range cases {
+		found, err := gopostgis.LineLocatePoint(measuredLine, c.point)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if math.Abs(found-c.expected) > 1e-12 {
+			t.Error("expected:", c.expected, "found:", found)
+		}
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.LineLocatePoint(measuredLine, gopostgis.PointS{SRID: 4326, Valid: true}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.LineLocatePoint(measuredLine, gopostgis.Point{}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestLineSubstring(t *testing.T) {
+	t.Run("across a vertex", func(t *testing.T) {
+		found, err := gopostgis.LineSubstring(measuredLine, 0.25, 0.75)
+		if err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		expected := []gopostgis.PointZMS{
+			{SRID: 3857, X: 5, Y: 0, Z: 5, M: 150, Valid: true},
+			measuredLine[1],
+			{SRID: 3857, X: 10, Y: 5, Z: 10, M: 250, Valid: true},
+		}
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("whole line", func(t *testing.T) {
+		found, err := gopostgis.LineSubstring(measuredLine, 0, 1)
+		if err != nil || !reflect.DeepEqual(found, measuredLine) {
+			t.Error("expected:", measuredLine, "found:", found, err)
+		}
+	})
+
+	t.Run("single point", func(t *testing.T) {
+		found, err := gopostgis.LineSubstring(measuredLine, 0.5, 0.5)
+		expected := []gopostgis.PointZMS{measuredLine[1]}
+		if err != nil || !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.LineSubstring(measuredLine, 0.8, 0.2); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.LineSubstring(measuredLine, -0.1, 0.2); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestLocateAlong(t *testing.T) {
+	t.Run("measure", func(t *testing.T) {
+		found, err := gopostgis.LocateAlong(measuredLine, 250, 0)
+		expected := []gopostgis.PointZMS{{SRID: 3857, X: 10, Y: 5, Z: 10, M: 250, Valid: true}}
+		if err != nil || !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("vertex", func(t *testing.T) {
+		found, err := gopostgis.LocateAlong(measuredLine, 200, 0)
+		expected := []gopostgis.PointZMS{measuredLine[1]}
+		if err != nil || !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		found, err := gopostgis.LocateAlong(measuredLine, 150, 2)
+		expected := []gopostgis.PointZMS{{SRID: 3857, X: 5, Y: 2, Z: 5, M: 150, Valid: true}}
+		if err != nil || !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+
+		found, err = gopostgis.LocateAlong(measuredLine, 250, 2)
+		expected = []gopostgis.PointZMS{{SRID: 3857, X: 8, Y: 5, Z: 10, M: 250, Valid: true}}
+		if err != nil || !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("repeated measures", func(t *testing.T) {
+		// measures going back and forth
+		line := []gopostgis.PointM{
+			{X: 0, Y: 0, M: 0, Valid: true},
+			{X: 10, Y: 0, M: 10, Valid: true},
+			{X: 20, Y: 0, M: 0, Valid: true},
+		}
+		found, err := gopostgis.LocateAlong(line, 5, 0)
+		expected := []gopostgis.PointM{{X: 5, Y: 0, M: 5, Valid: true}, {X: 15, Y: 0, M: 5, Valid: true}}
+		if err != nil || !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+
+		if found, err := gopostgis.LocateAlong(line, 20, 0); err != nil || found != nil {
+			t.Error("expected:", nil, "found:", found, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := gopostgis.LocateAlong(xyLine(0, 0, 1, 1), 0.5, 0); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
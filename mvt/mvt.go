@@ -0,0 +1,623 @@
+// Package mvt encodes go-postgis geometries into Mapbox Vector Tiles,
+// the equivalent of postgis ST_AsMVTGeom and ST_AsMVT.
+// reference - https://github.com/mapbox/vector-tile-spec/tree/master/2.1
+//
+// Geometries are expected in web mercator (SRID 3857). Point types having
+// SRID 4326 are projected to web mercator, types without SRID are
+// treated as web mercator.
+package mvt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// Default values of [Layer] fields.
+const (
+	DefaultExtent = 4096
+	DefaultBuffer = 256
+)
+
+// Half of the web mercator world size in meters.
+const mercatorMax = 20037508.342789244
+
+// Tile address in the XYZ tiling scheme.
+type Tile struct {
+	Z uint32
+	X uint32
+	Y uint32
+}
+
+// Bounds returns the web mercator bounds of the tile.
+func (t Tile) Bounds() (minX, minY, maxX, maxY float64) {
+	size := 2 * mercatorMax / float64(uint64(1)<<t.Z)
+	minX = -mercatorMax + float64(t.X)*size
+	maxY = mercatorMax - float64(t.Y)*size
+
+	return minX, maxY - size, minX + size, maxY
+}
+
+// Feature of a layer.
+type Feature struct {
+	// Optional identifier, 0 means no identifier.
+	ID uint64
+
+	// One of the go-postgis point types for a point, or a slice of a
+	// point type T: []T for a line string, [][]T for a polygon, the
+	// exterior ring followed by the interior rings, and [][][]T for a
+	// multi polygon.
+	Geometry any
+
+	// Encodes a []T Geometry as a multi point instead of a line string.
+	MultiPoint bool
+
+	// Values can be string, bool, any integer or float type. Nil values
+	// are skipped.
+	Properties map[string]any
+}
+
+// Layer of a tile.
+type Layer struct {
+	Name string
+
+	// Size of the tile in tile coordinates. Defaults to [DefaultExtent].
+	Extent uint32
+
+	// Geometries are clipped to Buffer tile coordinates outside of the
+	// tile. Nil means [DefaultBuffer].
+	Buffer *uint32
+
+	Features []Feature
+}
+
+// Encode encodes layers into a protobuf encoded tile t.
+// Features which are NULL, EMPTY or clipped entirely are dropped.
+func Encode(t Tile, layers ...Layer) ([]byte, error) {
+	if t.Z > 30 || uint64(t.X) >= uint64(1)<<t.Z || uint64(t.Y) >= uint64(1)<<t.Z {
+		return nil, fmt.Errorf("invalid tile. got: %d/%d/%d", t.Z, t.X, t.Y)
+	}
+
+	var tile []byte
+	for _, l := range layers {
+		b, err := encodeLayer(t, l)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+		}
+		tile = appendBytesField(tile, 3, b)
+	}
+
+	return tile, nil
+}
+
+// Key and value tables of a layer.
+type tags struct {
+	keys     []string
+	keyIndex map[string]uint32
+	values   [][]byte
+	valIndex map[string]uint32
+}
+
+func (t *tags) key(k string) uint32 {
+	if i, ok := t.keyIndex[k]; ok {
+		return i
+	}
+
+	i := uint32(len(t.keys))
+	t.keys = append(t.keys, k)
+	t.keyIndex[k] = i
+
+	return i
+}
+
+func (t *tags) value(v any) (uint32, error) {
+	b, err := encodeValue(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if i, ok := t.valIndex[string(b)]; ok {
+		return i, nil
+	}
+
+	i := uint32(len(t.values))
+	t.values = append(t.values, b)
+	t.valIndex[string(b)] = i
+
+	return i, nil
+}
+
+func encodeLayer(t Tile, l Layer) ([]byte, error) {
+	if l.Extent == 0 {
+		l.Extent = DefaultExtent
+	}
+	buffer := uint32(DefaultBuffer)
+	if l.Buffer != nil {
+		buffer = *l.Buffer
+	}
+
+	tt := tags{
+		keyIndex: make(map[string]uint32),
+		valIndex: make(map[string]uint32),
+	}
+
+	var features []byte
+	for i, f := range l.Features {
+		geomType, parts, err := geometry(f.Geometry, f.MultiPoint)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		commands := encodeGeometry(geomType, clip(t, l.Extent, buffer, geomType, parts))
+		if len(commands) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(f.Properties))
+		for k, v := range f.Properties {
+			if v != nil {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var ft []uint64
+		for _, k := range keys {
+			vi, err := tt.value(f.Properties[k])
+			if err != nil {
+				return nil, fmt.Errorf("feature %d property %q: %w", i, k, err)
+			}
+			ft = append(ft, uint64(tt.key(k)), uint64(vi))
+		}
+
+		var fb []byte
+		if f.ID != 0 {
+			fb = appendVarintField(fb, 1, f.ID)
+		}
+		if len(ft) > 0 {
+			fb = appendPackedField(fb, 2, ft)
+		}
+		fb = appendVarintField(fb, 3, uint64(geomType))
+		fb = appendPackedField(fb, 4, commands)
+
+		features = appendBytesField(features, 2, fb)
+	}
+
+	var b []byte
+	b = appendVarintField(b, 15, 2)
+	b = appendBytesField(b, 1, []byte(l.Name))
+	b = append(b, features...)
+	for _, k := range tt.keys {
+		b = appendBytesField(b, 3, []byte(k))
+	}
+	for _, v := range tt.values {
+		b = appendBytesField(b, 4, v)
+	}
+	b = appendVarintField(b, 5, uint64(l.Extent))
+
+	return b, nil
+}
+
+// Geometry types of MVT specification
+const (
+	geomTypePoint      = 1
+	geomTypeLineString = 2
+	geomTypePolygon    = 3
+)
+
+// Commands of MVT geometry encoding
+const (
+	cmdMoveTo    = 1
+	cmdLineTo    = 2
+	cmdClosePath = 7
+)
+
+// Web mercator coordinates of geometry g, as parts made of rings. A point
+// or a line string is a part of one ring, a multi point has a part per
+// point. NULL and EMPTY points are skipped.
+func geometry(g any, multiPoint bool) (int, [][][][2]float64, error) {
+	if g == nil {
+		return geomTypePoint, nil, nil
+	}
+
+	v := reflect.ValueOf(g)
+	if v.Kind() != reflect.Slice {
+		c, err := coordinates(g)
+		if err != nil || len(c) == 0 {
+			return geomTypePoint, nil, err
+		}
+		return geomTypePoint, [][][][2]float64{{c}}, nil
+	}
+
+	depth := 0
+	for t := v.Type(); t.Kind() == reflect.Slice; t = t.Elem() {
+		depth++
+	}
+
+	rings := func(v reflect.Value) ([][][2]float64, error) {
+		r := make([][][2]float64, v.Len())
+		for i := range r {
+			var err error
+			if r[i], err = line(v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+
+	switch depth {
+	case 1:
+		c, err := line(v)
+		if err != nil {
+			return 0, nil, err
+		}
+		if !multiPoint {
+			return geomTypeLineString, [][][][2]float64{{c}}, nil
+		}
+		parts := make([][][][2]float64, len(c))
+		for i, p := range c {
+			parts[i] = [][][2]float64{{p}}
+		}
+		return geomTypePoint, parts, nil
+
+	case 2:
+		r, err := rings(v)
+		return geomTypePolygon, [][][][2]float64{r}, err
+
+	case 3:
+		parts := make([][][][2]float64, v.Len())
+		for i := range parts {
+			var err error
+			if parts[i], err = rings(v.Index(i)); err != nil {
+				return 0, nil, err
+			}
+		}
+		return geomTypePolygon, parts, nil
+	}
+
+	return 0, nil, fmt.Errorf("geometry type not supported. got: %T", g)
+}
+
+// Web mercator coordinates of the points of slice v.
+func line(v reflect.Value) ([][2]float64, error) {
+	var coords [][2]float64
+	for i := 0; i < v.Len(); i++ {
+		c, err := coordinates(v.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		coords = append(coords, c...)
+	}
+
+	return coords, nil
+}
+
+// Converts parts of geometry type geomType into tile coordinates of tile t,
+// clipped to the tile extent grown by buffer. Points outside are dropped,
+// lines are cut into the pieces inside and rings are clipped to the box.
+// Parts whose exterior ring collapses are dropped.
+func clip(t Tile, extent, buffer uint32, geomType int, parts [][][][2]float64) [][][][2]int64 {
+	minX, minY, maxX, maxY := t.Bounds()
+	e := float64(extent)
+	lo, hi := -float64(buffer), e+float64(buffer)
+
+	toTile := func(r [][2]float64) [][2]float64 {
+		c := make([][2]float64, len(r))
+		for i, p := range r {
+			c[i] = [2]float64{(p[0] - minX) / (maxX - minX) * e, (maxY - p[1]) / (maxY - minY) * e}
+		}
+		return c
+	}
+
+	var clipped [][][][2]int64
+	for _, part := range parts {
+		switch geomType {
+		case geomTypePoint:
+			p := toTile(part[0])[0]
+			if p[0] >= lo && p[0] <= hi && p[1] >= lo && p[1] <= hi {
+				clipped = append(clipped, [][][2]int64{{round(p)}})
+			}
+
+		case geomTypeLineString:
+			for _, piece := range clipLine(toTile(part[0]), lo, hi) {
+				if r := roundRing(piece); len(r) >= 2 {
+					clipped = append(clipped, [][][2]int64{r})
+				}
+			}
+
+		case geomTypePolygon:
+			var rings [][][2]int64
+			for i, ring := range part {
+				r := roundRing(clipRing(toTile(ring), lo, hi))
+				if len(r) > 1 && r[0] == r[len(r)-1] {
+					r = r[:len(r)-1]
+				}
+
+				a := ringArea(r)
+				if len(r) < 3 || a == 0 {
+					if i == 0 {
+						break
+					}
+					continue
+				}
+
+				// exterior rings are clockwise on screen, which is a
+				// positive area with y pointing down
+				if (a > 0) != (i == 0) {
+					for j, k := 0, len(r)-1; j < k; j, k = j+1, k-1 {
+						r[j], r[k] = r[k], r[j]
+					}
+				}
+				rings = append(rings, r)
+			}
+			if len(rings) > 0 {
+				clipped = append(clipped, rings)
+			}
+		}
+	}
+
+	return clipped
+}
+
+func round(p [2]float64) [2]int64 {
+	return [2]int64{int64(math.Round(p[0])), int64(math.Round(p[1]))}
+}
+
+// Rounds points of r to tile coordinates, dropping repeated points.
+func roundRing(r [][2]float64) [][2]int64 {
+	var rounded [][2]int64
+	for _, p := range r {
+		q := round(p)
+		if n := len(rounded); n == 0 || rounded[n-1] != q {
+			rounded = append(rounded, q)
+		}
+	}
+
+	return rounded
+}
+
+// Twice the signed area of ring r.
+func ringArea(r [][2]int64) int64 {
+	var a int64
+	for i := range r {
+		p, q := r[i], r[(i+1)%len(r)]
+		a += p[0]*q[1] - q[0]*p[1]
+	}
+
+	return a
+}
+
+// Pieces of line inside the box lo, hi on both axes, by Liang-Barsky.
+func clipLine(line [][2]float64, lo, hi float64) [][][2]float64 {
+	var pieces [][][2]float64
+	var piece [][2]float64
+
+	for i := 1; i < len(line); i++ {
+		a, b := line[i-1], line[i]
+		t0, t1 := 0.0, 1.0
+		d := [2]float64{b[0] - a[0], b[1] - a[1]}
+		inside := true
+		for k := 0; k < 2 && inside; k++ {
+			for _, edge := range [2][2]float64{{-d[k], a[k] - lo}, {d[k], hi - a[k]}} {
+				p, q := edge[0], edge[1]
+				switch {
+				case p == 0 && q < 0:
+					inside = false
+				case p < 0:
+					t0 = math.Max(t0, q/p)
+				case p > 0:
+					t1 = math.Min(t1, q/p)
+				}
+			}
+		}
+		if !inside || t0 > t1 {
+			if len(piece) > 0 {
+				pieces, piece = append(pieces, piece), nil
+			}
+			continue
+		}
+
+		start := [2]float64{a[0] + t0*d[0], a[1] + t0*d[1]}
+		end := [2]float64{a[0] + t1*d[0], a[1] + t1*d[1]}
+		if len(piece) == 0 || t0 > 0 {
+			if len(piece) > 0 {
+				pieces = append(pieces, piece)
+			}
+			piece = [][2]float64{start}
+		}
+		piece = append(piece, end)
+		if t1 < 1 {
+			pieces, piece = append(pieces, piece), nil
+		}
+	}
+
+	if len(piece) > 0 {
+		pieces = append(pieces, piece)
+	}
+
+	return pieces
+}
+
+// Ring r clipped to the box lo, hi on both axes, by Sutherland-Hodgman.
+func clipRing(r [][2]float64, lo, hi float64) [][2]float64 {
+	for k := 0; k < 2; k++ {
+		for _, bound := range [2]float64{lo, hi} {
+			in := func(p [2]float64) bool {
+				if bound == lo {
+					return p[k] >= lo
+				}
+				return p[k] <= hi
+			}
+
+			var out [][2]float64
+			for i := range r {
+				a, b := r[i], r[(i+1)%len(r)]
+				if in(a) {
+					out = append(out, a)
+				}
+				if in(a) != in(b) {
+					t := (bound - a[k]) / (b[k] - a[k])
+					out = append(out, [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])})
+				}
+			}
+			r = out
+		}
+	}
+
+	return r
+}
+
+// Command encoding of clipped parts of geometry type geomType.
+func encodeGeometry(geomType int, parts [][][][2]int64) []uint64 {
+	var cmds []uint64
+	var cx, cy int64
+	moveTo := func(points [][2]int64) {
+		for _, p := range points {
+			cmds = append(cmds, zigzag(p[0]-cx), zigzag(p[1]-cy))
+			cx, cy = p[0], p[1]
+		}
+	}
+
+	if geomType == geomTypePoint {
+		if len(parts) > 0 {
+			cmds = append(cmds, uint64(cmdMoveTo|len(parts)<<3))
+		}
+		for _, part := range parts {
+			moveTo(part[0])
+		}
+		return cmds
+	}
+
+	for _, part := range parts {
+		for _, r := range part {
+			cmds = append(cmds, uint64(cmdMoveTo|1<<3))
+			moveTo(r[:1])
+			cmds = append(cmds, uint64(cmdLineTo|(len(r)-1)<<3))
+			moveTo(r[1:])
+			if geomType == geomTypePolygon {
+				cmds = append(cmds, uint64(cmdClosePath|1<<3))
+			}
+		}
+	}
+
+	return cmds
+}
+
+// Web mercator coordinates of point g. NULL and EMPTY points have none.
+func coordinates(g any) ([][2]float64, error) {
+	switch v := g.(type) {
+	case gopostgis.Point:
+		return point(v.Valid && !v.Empty, 0, v.X, v.Y)
+
+	case gopostgis.PointZ:
+		return point(v.Valid && !v.Empty, 0, v.X, v.Y)
+
+	case gopostgis.PointM:
+		return point(v.Valid && !v.Empty, 0, v.X, v.Y)
+
+	case gopostgis.PointZM:
+		return point(v.Valid && !v.Empty, 0, v.X, v.Y)
+
+	case gopostgis.PointS:
+		return point(v.Valid && !v.Empty, v.SRID, v.X, v.Y)
+
+	case gopostgis.PointZS:
+		return point(v.Valid && !v.Empty, v.SRID, v.X, v.Y)
+
+	case gopostgis.PointMS:
+		return point(v.Valid && !v.Empty, v.SRID, v.X, v.Y)
+
+	case gopostgis.PointZMS:
+		return point(v.Valid && !v.Empty, v.SRID, v.X, v.Y)
+	}
+
+	return nil, fmt.Errorf("geometry type not supported. got: %T", g)
+}
+
+func point(ok bool, srid uint32, x, y float64) ([][2]float64, error) {
+	if !ok {
+		return nil, nil
+	}
+
+	if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return nil, gopostgis.ErrNonFiniteCoordinate
+	}
+
+	switch srid {
+	case 0, 3857, 900913:
+		return [][2]float64{{x, y}}, nil
+
+	case 4326:
+		return [][2]float64{mercator(x, y)}, nil
+	}
+
+	return nil, fmt.Errorf("srid not supported. got: %d", srid)
+}
+
+// Maximum latitude of web mercator
+const maxLatitude = 85.0511287798066
+
+// Projects longitude/latitude to web mercator.
+func mercator(lon, lat float64) [2]float64 {
+	lat = math.Max(-maxLatitude, math.Min(maxLatitude, lat))
+
+	x := lon * mercatorMax / 180
+	y := math.Log(math.Tan((90+lat)*math.Pi/360)) / math.Pi * mercatorMax
+
+	return [2]float64{x, y}
+}
+
+// Encodes v as a Value message.
+func encodeValue(v any) ([]byte, error) {
+	var b []byte
+
+	switch v := v.(type) {
+	case string:
+		return appendBytesField(b, 1, []byte(v)), nil
+	case float32:
+		b = appendTag(b, 2, wireFixed32)
+		return appendFixed32(b, math.Float32bits(v)), nil
+	case float64:
+		b = appendTag(b, 3, wireFixed64)
+		return appendFixed64(b, math.Float64bits(v)), nil
+	case int:
+		return appendSint(b, int64(v)), nil
+	case int8:
+		return appendSint(b, int64(v)), nil
+	case int16:
+		return appendSint(b, int64(v)), nil
+	case int32:
+		return appendSint(b, int64(v)), nil
+	case int64:
+		return appendSint(b, v), nil
+	case uint:
+		return appendVarintField(b, 5, uint64(v)), nil
+	case uint8:
+		return appendVarintField(b, 5, uint64(v)), nil
+	case uint16:
+		return appendVarintField(b, 5, uint64(v)), nil
+	case uint32:
+		return appendVarintField(b, 5, uint64(v)), nil
+	case uint64:
+		return appendVarintField(b, 5, v), nil
+	case bool:
+		if v {
+			return appendVarintField(b, 7, 1), nil
+		}
+		return appendVarintField(b, 7, 0), nil
+	}
+
+	return nil, fmt.Errorf("property type not supported. got: %T", v)
+}
+
+// Non negative integers are encoded as uint_value, others as sint_value.
+func appendSint(b []byte, v int64) []byte {
+	if v >= 0 {
+		return appendVarintField(b, 5, uint64(v))
+	}
+
+	return appendVarintField(b, 6, zigzag(v))
+}
@@ -0,0 +1,365 @@
+package mvt_test
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+	"github.com/asif-mahmud/go-postgis/mvt"
+)
+
+// Minimal protobuf reader, returns fields of message b by number.
+// Varint fields are returned as their value, others as raw bytes.
+func fields(t *testing.T, b []byte) map[int][]interface{} {
+	m := make(map[int][]interface{})
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatal("invalid tag")
+		}
+		b = b[n:]
+		field := int(key >> 3)
+		switch key & 0x7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				t.Fatal("invalid varint")
+			}
+			b = b[n:]
+			m[field] = append(m[field], v)
+		case 1:
+			m[field] = append(m[field], b[:8])
+			b = b[8:]
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 {
+				t.Fatal("invalid length")
+			}
+			b = b[n:]
+			m[field] = append(m[field], b[:l])
+			b = b[l:]
+		case 5:
+			m[field] = append(m[field], b[:4])
+			b = b[4:]
+		default:
+			t.Fatal("unknown wire type")
+		}
+	}
+
+	return m
+}
+
+func packed(t *testing.T, b []byte) []uint64 {
+	var v []uint64
+	for len(b) > 0 {
+		x, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatal("invalid packed varint")
+		}
+		v = append(v, x)
+		b = b[n:]
+	}
+
+	return v
+}
+
+func TestTileBounds(t *testing.T) {
+	minX, minY, maxX, maxY := mvt.Tile{Z: 1, X: 1, Y: 0}.Bounds()
+	if minX != 0 || minY != 0 || maxX != 20037508.342789244 || maxY != 20037508.342789244 {
+		t.Error("unexpected bounds:", minX, minY, maxX, maxY)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tile := mvt.Tile{Z: 0, X: 0, Y: 0}
+	layer := mvt.Layer{
+		Name:   "points",
+		Extent: 4096,
+		Features: []mvt.Feature{
+			{
+				ID:       7,
+				Geometry: gopostgis.Point{X: 0, Y: 0, Valid: true},
+				Properties: map[string]any{
+					"name":  "center",
+					"count": 3,
+					"skip":  nil,
+				},
+			},
+			{
+				// projected to the top left corner of the world
+				Geometry: gopostgis.PointS{SRID: 4326, X: -180, Y: 85.0511287798066, Valid: true},
+				Properties: map[string]any{
+					"name": "corner",
+				},
+			},
+			{
+				Geometry: gopostgis.Point{Valid: false},
+			},
+			{
+				Geometry: gopostgis.PointZ{Empty: true, Valid: true},
+			},
+		},
+	}
+
+	b, e := mvt.Encode(tile, layer)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	layers := fields(t, b)[3]
+	if len(layers) != 1 {
+		t.Fatal("expected: 1 layer found:", len(layers))
+	}
+
+	l := fields(t, layers[0].([]byte))
+	if l[15][0].(uint64) != 2 || string(l[1][0].([]byte)) != "points" || l[5][0].(uint64) != 4096 {
+		t.Error("unexpected layer:", l)
+	}
+
+	keys := []string{}
+	for _, k := range l[3] {
+		keys = append(keys, string(k.([]byte)))
+	}
+	if !reflect.DeepEqual(keys, []string{"count", "name"}) {
+		t.Error("unexpected keys:", keys)
+	}
+	if len(l[4]) != 3 {
+		t.Error("expected: 3 values found:", len(l[4]))
+	}
+
+	features := l[2]
+	if len(features) != 2 {
+		t.Fatal("expected: 2 features found:", len(features))
+	}
+
+	f := fields(t, features[0].([]byte))
+	if f[1][0].(uint64) != 7 || f[3][0].(uint64) != 1 {
+		t.Error("unexpected feature:", f)
+	}
+	// count=3, name=center
+	if tags := packed(t, f[2][0].([]byte)); !reflect.DeepEqual(tags, []uint64{0, 0, 1, 1}) {
+		t.Error("unexpected tags:", tags)
+	}
+	// MoveTo(2048, 2048)
+	if geom := packed(t, f[4][0].([]byte)); !reflect.DeepEqual(geom, []uint64{9, 4096, 4096}) {
+		t.Error("unexpected geometry:", geom)
+	}
+
+	f = fields(t, features[1].([]byte))
+	if _, ok := f[1]; ok {
+		t.Error("feature without id should not have id field")
+	}
+	// MoveTo(0, 0)
+	if geom := packed(t, f[4][0].([]byte)); !reflect.DeepEqual(geom, []uint64{9, 0, 0}) {
+		t.Error("unexpected geometry:", geom)
+	}
+}
+
+func TestEncodeClip(t *testing.T) {
+	// the north east quarter of the world
+	tile := mvt.Tile{Z: 1, X: 1, Y: 0}
+	layer := mvt.Layer{
+		Name: "points",
+		Features: []mvt.Feature{
+			{
+				Geometry: []gopostgis.PointS{
+					{SRID: 3857, X: 10018754.171394622, Y: 10018754.171394622, Valid: true},
+					{SRID: 3857, X: -10018754.171394622, Y: 10018754.171394622, Valid: true},
+					{SRID: 3857, X: 20037508.342789244, Y: 0, Valid: true},
+				},
+				MultiPoint: true,
+			},
+			{
+				Geometry: gopostgis.Point{X: -10018754.171394622, Y: -10018754.171394622, Valid: true},
+			},
+		},
+	}
+
+	b, e := mvt.Encode(tile, layer)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	l := fields(t, fields(t, b)[3][0].([]byte))
+	if len(l[2]) != 1 {
+		t.Fatal("expected: 1 feature found:", len(l[2]))
+	}
+
+	f := fields(t, l[2][0].([]byte))
+	// MoveTo(2048, 2048), MoveTo(4096, 4096)
+	if geom := packed(t, f[4][0].([]byte)); !reflect.DeepEqual(geom, []uint64{17, 4096, 4096, 4096, 4096}) {
+		t.Error("unexpected geometry:", geom)
+	}
+}
+
+// Web mercator point at tile coordinates x, y of tile 0/0/0 of extent 4096.
+func tilePoint(x, y float64) gopostgis.PointZM {
+	size := 2 * 20037508.342789244 / 4096
+	return gopostgis.PointZM{X: x*size - 20037508.342789244, Y: 20037508.342789244 - y*size, Z: 1, M: 2, Valid: true}
+}
+
+func tileLine(coords ...float64) []gopostgis.PointZM {
+	var line []gopostgis.PointZM
+	for i := 0; i < len(coords); i += 2 {
+		line = append(line, tilePoint(coords[i], coords[i+1]))
+	}
+	return line
+}
+
+// Decodes geometry commands into lines of absolute tile coordinates,
+// every MoveTo starting a new line.
+func commands(t *testing.T, cmds []uint64) [][][2]int64 {
+	var lines [][][2]int64
+	var x, y int64
+	for i := 0; i < len(cmds); {
+		id, count := cmds[i]&0x7, int(cmds[i]>>3)
+		i++
+		if id == 7 {
+			continue
+		}
+		for j := 0; j < count; j++ {
+			x += int64(cmds[i]>>1) ^ -int64(cmds[i]&1)
+			y += int64(cmds[i+1]>>1) ^ -int64(cmds[i+1]&1)
+			i += 2
+			if id == 1 {
+				lines = append(lines, nil)
+			}
+			lines[len(lines)-1] = append(lines[len(lines)-1], [2]int64{x, y})
+		}
+	}
+
+	return lines
+}
+
+// Twice the signed area of ring r in tile coordinates.
+func area(r [][2]int64) int64 {
+	var a int64
+	for i := range r {
+		p, q := r[i], r[(i+1)%len(r)]
+		a += p[0]*q[1] - q[0]*p[1]
+	}
+	return a
+}
+
+func TestEncodeGeometries(t *testing.T) {
+	zero := uint32(0)
+	encode := func(t *testing.T, buffer *uint32, f mvt.Feature) (uint64, [][][2]int64) {
+		b, e := mvt.Encode(mvt.Tile{}, mvt.Layer{Name: "l", Buffer: buffer, Features: []mvt.Feature{f}})
+		if e != nil {
+			t.Fatal(e)
+		}
+		features := fields(t, fields(t, b)[3][0].([]byte))[2]
+		if len(features) == 0 {
+			return 0, nil
+		}
+		ft := fields(t, features[0].([]byte))
+		return ft[3][0].(uint64), commands(t, packed(t, ft[4][0].([]byte)))
+	}
+
+	t.Run("zero buffer", func(t *testing.T) {
+		p := mvt.Feature{Geometry: tilePoint(-10, 5)}
+		if _, found := encode(t, &zero, p); found != nil {
+			t.Error("expected: clipped found:", found)
+		}
+		if _, found := encode(t, nil, p); !reflect.DeepEqual(found, [][][2]int64{{{-10, 5}}}) {
+			t.Error("expected: [[[-10 5]]] found:", found)
+		}
+	})
+
+	t.Run("line string", func(t *testing.T) {
+		line := mvt.Feature{Geometry: tileLine(-100, 100, 100, 100, 100, -100)}
+
+		geomType, found := encode(t, &zero, line)
+		expected := [][][2]int64{{{0, 100}, {100, 100}, {100, 0}}}
+		if geomType != 2 || !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", geomType, found)
+		}
+
+		_, found = encode(t, nil, line)
+		expected = [][][2]int64{{{-100, 100}, {100, 100}, {100, -100}}}
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+
+		// leaves and enters the tile again
+		line.Geometry = tileLine(10, 10, 10, -50, 20, -50, 20, 10)
+		_, found = encode(t, &zero, line)
+		expected = [][][2]int64{{{10, 10}, {10, 0}}, {{20, 0}, {20, 10}}}
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+
+		line.Geometry = tileLine(-10, -10, -20, -30)
+		if _, found := encode(t, &zero, line); found != nil {
+			t.Error("expected: clipped found:", found)
+		}
+	})
+
+	t.Run("polygon", func(t *testing.T) {
+		polygon := mvt.Feature{Geometry: [][]gopostgis.PointZM{
+			tileLine(-100, -100, 100, -100, 100, 100, -100, 100, -100, -100),
+			tileLine(40, 40, 60, 40, 60, 60, 40, 60, 40, 40),
+			tileLine(-50, -50, -40, -50, -40, -40, -50, -50),
+		}}
+
+		geomType, found := encode(t, &zero, polygon)
+		if geomType != 3 || len(found) != 2 {
+			t.Fatal("expected: 2 rings found:", geomType, found)
+		}
+		for _, c := range found[0] {
+			if (c[0] != 0 && c[0] != 100) || (c[1] != 0 && c[1] != 100) {
+				t.Error("expected: clipped exterior found:", found[0])
+			}
+		}
+		if a := area(found[0]); a != 20000 {
+			t.Error("expected: 20000 found:", a)
+		}
+		if a := area(found[1]); a != -800 {
+			t.Error("expected: -800 found:", a)
+		}
+
+		polygon.Geometry = [][][]gopostgis.PointZM{
+			{tileLine(-30, -30, -20, -30, -20, -20, -30, -30)},
+			{tileLine(10, 10, 20, 10, 20, 20, 10, 10)},
+		}
+		_, found = encode(t, &zero, polygon)
+		if len(found) != 1 || area(found[0]) != 100 {
+			t.Error("expected: 1 ring found:", found)
+		}
+	})
+}
+
+func TestEncodeErrors(t *testing.T) {
+	if _, e := mvt.Encode(mvt.Tile{Z: 1, X: 2, Y: 0}); e == nil {
+		t.Error("invalid tile should fail")
+	}
+
+	layer := mvt.Layer{
+		Name: "points",
+		Features: []mvt.Feature{
+			{Geometry: gopostgis.PointS{SRID: 27700, X: 1, Y: 1, Valid: true}},
+		},
+	}
+	if _, e := mvt.Encode(mvt.Tile{}, layer); e == nil {
+		t.Error("unsupported srid should fail")
+	}
+
+	layer.Features = []mvt.Feature{
+		{Geometry: gopostgis.Point{X: math.NaN(), Y: 1, Valid: true}},
+	}
+	if _, e := mvt.Encode(mvt.Tile{}, layer); e == nil {
+		t.Error("non-finite coordinate should fail")
+	}
+
+	layer.Features = []mvt.Feature{
+		{
+			Geometry:   gopostgis.Point{Valid: true},
+			Properties: map[string]any{"bad": []int{1}},
+		},
+	}
+	if _, e := mvt.Encode(mvt.Tile{}, layer); e == nil {
+		t.Error("unsupported property should fail")
+	}
+}
@@ -0,0 +1,581 @@
+package gopostgis
+
+import (
+	"math"
+	"sort"
+)
+
+// Polygon overlay by boundary classification. Pieces of two groups are
+// noded against each other, and every piece of boundary is kept or dropped
+// by whether it lies inside, outside or on the boundary of the other group.
+// Each group is the union of its pieces, so overlapping pieces are fine.
+// reference - https://doi.org/10.1145/356802.356803 (Weiler-Atherton)
+
+type overlayOp int
+
+const (
+	overlayUnion overlayOp = iota
+	overlayIntersection
+	overlayDifference
+	overlaySymDifference
+)
+
+// Polygon operand of an overlay, the first ring is the exterior.
+type overlayPiece struct {
+	rings [][][2]float64
+	group int
+	env   Envelope
+}
+
+func newOverlayPiece(rings [][][2]float64, group int) overlayPiece {
+	p := overlayPiece{group: group}
+	p.env = Envelope{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+
+	for i, r := range rings {
+		r = openRing(r)
+		if len(r) < 3 {
+			if i == 0 {
+				return overlayPiece{group: group}
+			}
+			continue
+		}
+
+		// interior on the left of every edge
+		if ccw := ringArea(r) > 0; ccw != (i == 0) {
+			r = reversedRing(r)
+		}
+
+		for _, c := range r {
+			p.env.add(c[0], c[1])
+		}
+		p.rings = append(p.rings, r)
+	}
+
+	return p
+}
+
+// Drops the closing point and repeated points of r.
+func openRing(r [][2]float64) [][2]float64 {
+	var open [][2]float64
+	for _, c := range r {
+		if len(open) == 0 || open[len(open)-1] != c {
+			open = append(open, c)
+		}
+	}
+	if len(open) > 1 && open[0] == open[len(open)-1] {
+		open = open[:len(open)-1]
+	}
+
+	return open
+}
+
+func reversedRing(r [][2]float64) [][2]float64 {
+	rev := make([][2]float64, len(r))
+	for i, c := range r {
+		rev[len(r)-1-i] = c
+	}
+
+	return rev
+}
+
+// Signed area of open ring r, positive when counter clockwise.
+func ringArea(r [][2]float64) float64 {
+	var a float64
+	for i := range r {
+		p, q := r[i], r[(i+1)%len(r)]
+		a += p[0]*q[1] - q[0]*p[1]
+	}
+
+	return a / 2
+}
+
+// Even-odd test of c against the rings of p.
+func (p *overlayPiece) contains(c [2]float64) bool {
+	if c[0] < p.env.MinX || c[0] > p.env.MaxX || c[1] < p.env.MinY || c[1] > p.env.MaxY {
+		return false
+	}
+
+	inside := false
+	for _, r := range p.rings {
+		for i := range r {
+			a, b := r[i], r[(i+1)%len(r)]
+			if (a[1] > c[1]) != (b[1] > c[1]) && c[0] < a[0]+(c[1]-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+type overlayEdge struct {
+	a, b   [2]float64
+	piece  int
+	splits [][2]float64
+}
+
+type overlayKey struct {
+	a, b [2]float64
+}
+
+type overlaySubEdge struct {
+	a, b     [2]float64
+	piece    int
+	boundary bool // on the boundary of the union of its group
+	dup      bool // same as a sub edge of a previous piece of its group
+}
+
+// Snaps nearby points to the same node, so edges split at an intersection
+// share their end points exactly.
+type overlayNodes struct {
+	eps   float64
+	cells map[[2]int64][][2]float64
+}
+
+func (n *overlayNodes) snap(c [2]float64) [2]float64 {
+	cx, cy := int64(math.Floor(c[0]/n.eps)), int64(math.Floor(c[1]/n.eps))
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, node := range n.cells[[2]int64{cx + dx, cy + dy}] {
+				if math.Abs(node[0]-c[0]) <= n.eps && math.Abs(node[1]-c[1]) <= n.eps {
+					return node
+				}
+			}
+		}
+	}
+
+	key := [2]int64{cx, cy}
+	n.cells[key] = append(n.cells[key], c)
+
+	return c
+}
+
+// Overlays pieces of group 0 and 1 with op, returns polygons with the
+// exterior ring counter clockwise followed by clockwise interior rings.
+// Rings are open, without the closing point.
+func overlay(pieces []overlayPiece, op overlayOp) [][][][2]float64 {
+	var scale float64
+	for _, p := range pieces {
+		for _, r := range p.rings {
+			for _, c := range r {
+				scale = math.Max(scale, math.Max(math.Abs(c[0]), math.Abs(c[1])))
+			}
+		}
+	}
+	nodes := &overlayNodes{
+		eps:   math.Max(scale, 1) * 1e-10,
+		cells: map[[2]int64][][2]float64{},
+	}
+
+	var edges []*overlayEdge
+	for i := range pieces {
+		for r, ring := range pieces[i].rings {
+			for j := range ring {
+				ring[j] = nodes.snap(ring[j])
+			}
+			pieces[i].rings[r] = openRing(ring)
+		}
+		for _, ring := range pieces[i].rings {
+			for j := range ring {
+				a, b := ring[j], ring[(j+1)%len(ring)]
+				if a != b {
+					edges = append(edges, &overlayEdge{a: a, b: b, piece: i})
+				}
+			}
+		}
+	}
+
+	nodeEdges(edges, nodes)
+
+	subs, index := splitEdges(edges)
+	classifyOwnGroup(subs, index, pieces)
+
+	var kept []overlaySubEdge
+	for _, s := range subs {
+		if !s.boundary || s.dup {
+			continue
+		}
+
+		status := otherGroupStatus(s, index, pieces, subs)
+		forward, reverse := overlaySelect(op, pieces[s.piece].group, status)
+		switch {
+		case forward:
+			kept = append(kept, s)
+		case reverse:
+			s.a, s.b = s.b, s.a
+			kept = append(kept, s)
+		}
+	}
+
+	return buildPolygons(kept, nodes.eps)
+}
+
+const (
+	statusOut = iota
+	statusIn
+	statusSame
+	statusOpposite
+)
+
+// Which sub edges of group, by their status against the other group, make
+// the boundary of the result. Shared edges are only taken from group 0.
+func overlaySelect(op overlayOp, group, status int) (forward bool, reverse bool) {
+	switch op {
+	case overlayUnion:
+		return status == statusOut || (status == statusSame && group == 0), false
+	case overlayIntersection:
+		return status == statusIn || (status == statusSame && group == 0), false
+	case overlayDifference:
+		if group == 0 {
+			return status == statusOut || status == statusOpposite, false
+		}
+		return false, status == statusIn
+	case overlaySymDifference:
+		return status == statusOut, status == statusIn
+	}
+
+	return false, false
+}
+
+// Splits edges at their intersections with each other.
+func nodeEdges(edges []*overlayEdge, nodes *overlayNodes) {
+	sorted := make([]*overlayEdge, len(edges))
+	copy(sorted, edges)
+	sort.Slice(sorted, func(i, j int) bool {
+		return math.Min(sorted[i].a[0], sorted[i].b[0]) < math.Min(sorted[j].a[0], sorted[j].b[0])
+	})
+
+	eps := nodes.eps
+	for i, e := range sorted {
+		maxX := math.Max(e.a[0], e.b[0]) + eps
+		minY, maxY := math.Min(e.a[1], e.b[1])-eps, math.Max(e.a[1], e.b[1])+eps
+
+		for _, f := range sorted[i+1:] {
+			if math.Min(f.a[0], f.b[0]) > maxX {
+				break
+			}
+			if math.Max(f.a[1], f.b[1]) < minY || math.Min(f.a[1], f.b[1]) > maxY {
+				continue
+			}
+			intersectEdges(e, f, nodes)
+		}
+	}
+}
+
+func intersectEdges(e, f *overlayEdge, nodes *overlayNodes) {
+	eps := nodes.eps
+
+	// end points touching the other edge, including collinear overlaps
+	touch := false
+	for _, c := range [2][2]float64{f.a, f.b} {
+		if c != e.a && c != e.b && segmentDistance(c, e.a, e.b) <= eps {
+			e.splits = append(e.splits, c)
+			touch = true
+		}
+	}
+	for _, c := range [2][2]float64{e.a, e.b} {
+		if c != f.a && c != f.b && segmentDistance(c, f.a, f.b) <= eps {
+			f.splits = append(f.splits, c)
+			touch = true
+		}
+	}
+	if touch {
+		return
+	}
+
+	d1 := orientation(f.a, f.b, e.a)
+	d2 := orientation(f.a, f.b, e.b)
+	d3 := orientation(e.a, e.b, f.a)
+	d4 := orientation(e.a, e.b, f.b)
+	if !(((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))) {
+		return
+	}
+
+	t := d1 / (d1 - d2)
+	c := nodes.snap([2]float64{e.a[0] + t*(e.b[0]-e.a[0]), e.a[1] + t*(e.b[1]-e.a[1])})
+	if c != e.a && c != e.b {
+		e.splits = append(e.splits, c)
+	}
+	if c != f.a && c != f.b {
+		f.splits = append(f.splits, c)
+	}
+}
+
+// Splits edges into sub edges, indexed by their end points.
+func splitEdges(edges []*overlayEdge) ([]overlaySubEdge, map[overlayKey][]int) {
+	var subs []overlaySubEdge
+	index := map[overlayKey][]int{}
+
+	for _, e := range edges {
+		dx, dy := e.b[0]-e.a[0], e.b[1]-e.a[1]
+		param := func(c [2]float64) float64 {
+			return ((c[0]-e.a[0])*dx + (c[1]-e.a[1])*dy) / (dx*dx + dy*dy)
+		}
+		sort.Slice(e.splits, func(i, j int) bool { return param(e.splits[i]) < param(e.splits[j]) })
+
+		points := append(append([][2]float64{e.a}, e.splits...), e.b)
+		for i := 1; i < len(points); i++ {
+			a, b := points[i-1], points[i]
+			if a == b {
+				continue
+			}
+			key := overlayKey{a, b}
+			index[key] = append(index[key], len(subs))
+			subs = append(subs, overlaySubEdge{a: a, b: b, piece: e.piece})
+		}
+	}
+
+	return subs, index
+}
+
+func midpoint(a, b [2]float64) [2]float64 {
+	return [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+}
+
+// Marks the sub edges on the boundary of the union of their group.
+func classifyOwnGroup(subs []overlaySubEdge, index map[overlayKey][]int, pieces []overlayPiece) {
+	for i := range subs {
+		s := &subs[i]
+		group := pieces[s.piece].group
+		s.boundary = true
+
+		shared := map[int]bool{s.piece: true}
+		for _, j := range index[overlayKey{s.b, s.a}] {
+			o := subs[j]
+			if pieces[o.piece].group == group && o.piece != s.piece {
+				// group interior on both sides
+				s.boundary = false
+			}
+			shared[o.piece] = true
+		}
+		for _, j := range index[overlayKey{s.a, s.b}] {
+			o := subs[j]
+			if pieces[o.piece].group == group && o.piece < s.piece {
+				s.dup = true
+			}
+			shared[o.piece] = true
+		}
+		if !s.boundary {
+			continue
+		}
+
+		m := midpoint(s.a, s.b)
+		for p := range pieces {
+			if !shared[p] && pieces[p].group == group && pieces[p].contains(m) {
+				s.boundary = false
+				break
+			}
+		}
+	}
+}
+
+// Status of boundary sub edge s against the union of the other group.
+func otherGroupStatus(s overlaySubEdge, index map[overlayKey][]int, pieces []overlayPiece, subs []overlaySubEdge) int {
+	group := pieces[s.piece].group
+
+	for _, dir := range [2]overlayKey{{s.a, s.b}, {s.b, s.a}} {
+		for _, j := range index[dir] {
+			o := subs[j]
+			if pieces[o.piece].group == group {
+				continue
+			}
+			if !o.boundary {
+				return statusIn
+			}
+			if dir.a == s.a {
+				return statusSame
+			}
+			return statusOpposite
+		}
+	}
+
+	m := midpoint(s.a, s.b)
+	for p := range pieces {
+		if pieces[p].group != group && pieces[p].contains(m) {
+			return statusIn
+		}
+	}
+
+	return statusOut
+}
+
+// Links directed edges into rings and groups them into polygons.
+func buildPolygons(edges []overlaySubEdge, eps float64) [][][][2]float64 {
+	out := map[[2]float64][]int{}
+	for i, e := range edges {
+		out[e.a] = append(out[e.a], i)
+	}
+
+	used := make([]bool, len(edges))
+	var shells, holes [][][2]float64
+
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+
+		var ring [][2]float64
+		closed := false
+		for i := start; i >= 0 && !used[i]; {
+			used[i] = true
+			e := edges[i]
+			ring = append(ring, e.a)
+
+			// sharpest left turn keeps the interior on the left
+			back := math.Atan2(e.a[1]-e.b[1], e.a[0]-e.b[0])
+			next, best := -1, math.Inf(1)
+			for _, j := range out[e.b] {
+				if used[j] && j != start {
+					continue
+				}
+				f := edges[j]
+				turn := back - math.Atan2(f.b[1]-f.a[1], f.b[0]-f.a[0])
+				for turn <= 0 {
+					turn += 2 * math.Pi
+				}
+				if turn < best {
+					next, best = j, turn
+				}
+			}
+			if next == start {
+				closed = true
+				break
+			}
+			i = next
+		}
+
+		if !closed {
+			continue
+		}
+
+		for _, r := range splitRing(openRing(ring)) {
+			if len(r) < 3 {
+				continue
+			}
+			switch a := ringArea(r); {
+			case a > eps*eps:
+				shells = append(shells, r)
+			case a < -eps*eps:
+				holes = append(holes, r)
+			}
+		}
+	}
+
+	polygons := make([][][][2]float64, len(shells))
+	areas := make([]float64, len(shells))
+	for i, s := range shells {
+		polygons[i] = [][][2]float64{s}
+		areas[i] = ringArea(s)
+	}
+
+	for _, h := range holes {
+		// smallest shell containing the hole
+		m := midpoint(h[0], h[1])
+		owner := -1
+		for i, s := range shells {
+			p := overlayPiece{rings: [][][2]float64{s}, env: ringEnvelope(s)}
+			if p.contains(m) && (owner < 0 || areas[i] < areas[owner]) {
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			polygons[owner] = append(polygons[owner], h)
+		}
+	}
+
+	return polygons
+}
+
+// Splits an open ring at repeated nodes into rings visiting every node
+// once, so a ring touching itself becomes a shell and a hole, or two
+// shells touching at a vertex.
+func splitRing(ring [][2]float64) [][][2]float64 {
+	var rings [][][2]float64
+	var path [][2]float64
+	at := map[[2]float64]int{}
+
+	for _, c := range ring {
+		if k, ok := at[c]; ok {
+			rings = append(rings, append([][2]float64(nil), path[k:]...))
+			for _, d := range path[k:] {
+				delete(at, d)
+			}
+			path = path[:k]
+		}
+		at[c] = len(path)
+		path = append(path, c)
+	}
+
+	return append(rings, path)
+}
+
+func ringEnvelope(r [][2]float64) Envelope {
+	e := Envelope{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	for _, c := range r {
+		e.add(c[0], c[1])
+	}
+
+	return e
+}
+
+// Planar coordinates of the rings of polygon.
+func polygonXY[T PointType](polygon [][]T) [][][2]float64 {
+	rings := make([][][2]float64, len(polygon))
+	for i, r := range polygon {
+		rings[i] = pointsXY(r)
+	}
+
+	return rings
+}
+
+// Builds a point of type T at x, y in srid.
+func makePoint[T PointType, P interface {
+	*T
+	setData(pointData)
+}](x, y float64, srid uint32) T {
+	var p T
+	d := p.data()
+	d.srid = srid
+	d.coords[0], d.coords[1] = x, y
+	d.valid = true
+	P(&p).setData(d)
+
+	return p
+}
+
+// Converts an overlay polygon into T rings, the exterior ring clockwise
+// and interior rings counter clockwise, all of them closed.
+func toPolygon[T PointType, P interface {
+	*T
+	setData(pointData)
+}](shell [][2]float64, holes [][][2]float64, srid uint32) [][]T {
+	rings := append([][][2]float64{shell}, holes...)
+	polygon := make([][]T, len(rings))
+
+	for i, r := range rings {
+		r = openRing(r)
+		if ccw := ringArea(r) > 0; ccw == (i == 0) {
+			r = reversedRing(r)
+		}
+
+		ring := make([]T, 0, len(r)+1)
+		for _, c := range r {
+			ring = append(ring, makePoint[T, P](c[0], c[1], srid))
+		}
+		polygon[i] = append(ring, ring[0])
+	}
+
+	return polygon
+}
+
+func toMultiPolygon[T PointType, P interface {
+	*T
+	setData(pointData)
+}](polygons [][][][2]float64, srid uint32) [][][]T {
+	var multi [][][]T
+	for _, p := range polygons {
+		multi = append(multi, toPolygon[T, P](p[0], p[1:], srid))
+	}
+
+	return multi
+}
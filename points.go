@@ -2,735 +2,810 @@ package gopostgis
 
 import (
 	"database/sql/driver"
-	"encoding/json"
-	"fmt"
 )
 
+// Every point type below is a thin wrapper around the shared codec,
+// converting itself to and from pointData.
+
 var (
-	jsonNullString = "null"
-	jsonNullValue  = []byte(jsonNullString)
+	layoutXYS   = layoutXY.withSRID()
+	layoutXYZS  = layoutXYZ.withSRID()
+	layoutXYMS  = layoutXYM.withSRID()
+	layoutXYZMS = layoutXYZM.withSRID()
 )
 
 // Point (X, Y) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type Point struct {
 	X     float64
 	Y     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p Point) data() pointData {
+	return pointData{
+		layout: layoutXY,
+		coords: [4]float64{p.X, p.Y},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *Point) setData(d pointData) {
+	p.X, p.Y = d.coords[0], d.coords[1]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *Point) Scan(src any) error {
-	if src == nil {
-		p.X = 0
-		p.Y = 0
-		p.Valid = false
-		return nil
+	d, e := scanPoint(src, layoutXY)
+	if e != nil {
+		return e
 	}
 
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 2)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.X = values[0]
-		p.Y = values[1]
-		p.Valid = true
-		return nil
+	p.setData(d)
 
-	default:
-		return fmt.Errorf("driver datatype not supported")
-	}
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p Point) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("POINT(%g %g)", p.X, p.Y), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *Point) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.X = 0
-		p.Y = 0
-		p.Valid = false
-		return nil
+func (p *Point) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXY)
+	if e != nil {
+		return e
 	}
 
-	type point Point
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p Point) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p Point) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p Point) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *Point) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXY)
+	if e != nil {
+		return e
 	}
 
-	p.X = tp.X
-	p.Y = tp.Y
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p Point) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p Point) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *Point) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXY)
+	if e != nil {
+		return e
 	}
 
-	type point Point
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
 
 // PointS (SRID X, Y) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type PointS struct {
 	SRID  uint32
 	X     float64
 	Y     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p PointS) data() pointData {
+	return pointData{
+		layout: layoutXYS,
+		srid:   p.SRID,
+		coords: [4]float64{p.X, p.Y},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *PointS) setData(d pointData) {
+	p.SRID = d.srid
+	p.X, p.Y = d.coords[0], d.coords[1]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *PointS) Scan(src any) error {
-	if src == nil {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.Valid = false
-		return nil
-	}
-
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		srid, e := d.ReadUint32()
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 2)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.SRID = srid
-		p.X = values[0]
-		p.Y = values[1]
-		p.Valid = true
-		return nil
-
-	default:
-		return fmt.Errorf("driver datatype not supported")
+	d, e := scanPoint(src, layoutXYS)
+	if e != nil {
+		return e
 	}
+
+	p.setData(d)
+
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p PointS) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("SRID=%d;POINT(%g %g)", p.SRID, p.X, p.Y), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *PointS) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.Valid = false
-		return nil
+func (p *PointS) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXYS)
+	if e != nil {
+		return e
 	}
 
-	type point PointS
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p PointS) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p PointS) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p PointS) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *PointS) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXYS)
+	if e != nil {
+		return e
 	}
 
-	p.SRID = tp.SRID
-	p.X = tp.X
-	p.Y = tp.Y
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p PointS) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p PointS) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *PointS) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXYS)
+	if e != nil {
+		return e
 	}
 
-	type point PointS
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
 
 // PointZ (X, Y, Z) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type PointZ struct {
 	X     float64
 	Y     float64
 	Z     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p PointZ) data() pointData {
+	return pointData{
+		layout: layoutXYZ,
+		coords: [4]float64{p.X, p.Y, p.Z},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *PointZ) setData(d pointData) {
+	p.X, p.Y, p.Z = d.coords[0], d.coords[1], d.coords[2]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *PointZ) Scan(src any) error {
-	if src == nil {
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.Valid = false
-		return nil
-	}
-
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 3)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.X = values[0]
-		p.Y = values[1]
-		p.Z = values[2]
-		p.Valid = true
-		return nil
-
-	default:
-		return fmt.Errorf("driver datatype not supported")
+	d, e := scanPoint(src, layoutXYZ)
+	if e != nil {
+		return e
 	}
+
+	p.setData(d)
+
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p PointZ) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("POINT(%g %g %g)", p.X, p.Y, p.Z), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *PointZ) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.Valid = false
-		return nil
+func (p *PointZ) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXYZ)
+	if e != nil {
+		return e
 	}
 
-	type point PointZ
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p PointZ) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p PointZ) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p PointZ) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *PointZ) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXYZ)
+	if e != nil {
+		return e
 	}
 
-	p.X = tp.X
-	p.Y = tp.Y
-	p.Z = tp.Z
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p PointZ) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p PointZ) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *PointZ) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXYZ)
+	if e != nil {
+		return e
 	}
 
-	type point PointZ
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
 
 // PointZS (SRID X, Y, Z) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type PointZS struct {
 	SRID  uint32
 	X     float64
 	Y     float64
 	Z     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p PointZS) data() pointData {
+	return pointData{
+		layout: layoutXYZS,
+		srid:   p.SRID,
+		coords: [4]float64{p.X, p.Y, p.Z},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *PointZS) setData(d pointData) {
+	p.SRID = d.srid
+	p.X, p.Y, p.Z = d.coords[0], d.coords[1], d.coords[2]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *PointZS) Scan(src any) error {
-	if src == nil {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.Valid = false
-		return nil
-	}
-
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		srid, e := d.ReadUint32()
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 3)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.SRID = srid
-		p.X = values[0]
-		p.Y = values[1]
-		p.Z = values[2]
-		p.Valid = true
-		return nil
-
-	default:
-		return fmt.Errorf("driver datatype not supported")
+	d, e := scanPoint(src, layoutXYZS)
+	if e != nil {
+		return e
 	}
+
+	p.setData(d)
+
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p PointZS) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("SRID=%d;POINT(%g %g %g)", p.SRID, p.X, p.Y, p.Z), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *PointZS) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.Valid = false
-		return nil
+func (p *PointZS) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXYZS)
+	if e != nil {
+		return e
 	}
 
-	type point PointZS
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p PointZS) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p PointZS) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p PointZS) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *PointZS) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXYZS)
+	if e != nil {
+		return e
 	}
 
-	p.SRID = tp.SRID
-	p.X = tp.X
-	p.Y = tp.Y
-	p.Z = tp.Z
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p PointZS) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p PointZS) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *PointZS) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXYZS)
+	if e != nil {
+		return e
 	}
 
-	type point PointZS
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
 
 // PointM (X, Y, M) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type PointM struct {
 	X     float64
 	Y     float64
 	M     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p PointM) data() pointData {
+	return pointData{
+		layout: layoutXYM,
+		coords: [4]float64{p.X, p.Y, p.M},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *PointM) setData(d pointData) {
+	p.X, p.Y, p.M = d.coords[0], d.coords[1], d.coords[2]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *PointM) Scan(src any) error {
-	if src == nil {
-		p.X = 0
-		p.Y = 0
-		p.M = 0
-		p.Valid = false
-		return nil
-	}
-
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 3)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.X = values[0]
-		p.Y = values[1]
-		p.M = values[2]
-		p.Valid = true
-		return nil
-
-	default:
-		return fmt.Errorf("driver datatype not supported")
+	d, e := scanPoint(src, layoutXYM)
+	if e != nil {
+		return e
 	}
+
+	p.setData(d)
+
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p PointM) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("POINT(%g %g %g)", p.X, p.Y, p.M), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *PointM) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.X = 0
-		p.Y = 0
-		p.M = 0
-		p.Valid = false
-		return nil
+func (p *PointM) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXYM)
+	if e != nil {
+		return e
 	}
 
-	type point PointM
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p PointM) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p PointM) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p PointM) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *PointM) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXYM)
+	if e != nil {
+		return e
 	}
 
-	p.X = tp.X
-	p.Y = tp.Y
-	p.M = tp.M
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p PointM) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p PointM) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *PointM) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXYM)
+	if e != nil {
+		return e
 	}
 
-	type point PointM
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
 
 // PointMS (SRID X, Y, M) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type PointMS struct {
 	SRID  uint32
 	X     float64
 	Y     float64
 	M     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p PointMS) data() pointData {
+	return pointData{
+		layout: layoutXYMS,
+		srid:   p.SRID,
+		coords: [4]float64{p.X, p.Y, p.M},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *PointMS) setData(d pointData) {
+	p.SRID = d.srid
+	p.X, p.Y, p.M = d.coords[0], d.coords[1], d.coords[2]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *PointMS) Scan(src any) error {
-	if src == nil {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.M = 0
-		p.Valid = false
-		return nil
-	}
-
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		srid, e := d.ReadUint32()
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 3)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.SRID = srid
-		p.X = values[0]
-		p.Y = values[1]
-		p.M = values[2]
-		p.Valid = true
-		return nil
-
-	default:
-		return fmt.Errorf("driver datatype not supported")
+	d, e := scanPoint(src, layoutXYMS)
+	if e != nil {
+		return e
 	}
+
+	p.setData(d)
+
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p PointMS) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("SRID=%d;POINT(%g %g %g)", p.SRID, p.X, p.Y, p.M), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *PointMS) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.M = 0
-		p.Valid = false
-		return nil
+func (p *PointMS) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXYMS)
+	if e != nil {
+		return e
 	}
 
-	type point PointMS
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p PointMS) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p PointMS) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p PointMS) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *PointMS) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXYMS)
+	if e != nil {
+		return e
 	}
 
-	p.SRID = tp.SRID
-	p.X = tp.X
-	p.Y = tp.Y
-	p.M = tp.M
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p PointMS) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p PointMS) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *PointMS) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXYMS)
+	if e != nil {
+		return e
 	}
 
-	type point PointMS
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
 
 // PointZM (X, Y, Z, M) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type PointZM struct {
 	X     float64
 	Y     float64
 	Z     float64
 	M     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p PointZM) data() pointData {
+	return pointData{
+		layout: layoutXYZM,
+		coords: [4]float64{p.X, p.Y, p.Z, p.M},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *PointZM) setData(d pointData) {
+	p.X, p.Y, p.Z, p.M = d.coords[0], d.coords[1], d.coords[2], d.coords[3]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *PointZM) Scan(src any) error {
-	if src == nil {
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.M = 0
-		p.Valid = false
-		return nil
-	}
-
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 4)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.X = values[0]
-		p.Y = values[1]
-		p.Z = values[2]
-		p.M = values[3]
-		p.Valid = true
-		return nil
-
-	default:
-		return fmt.Errorf("driver datatype not supported")
+	d, e := scanPoint(src, layoutXYZM)
+	if e != nil {
+		return e
 	}
+
+	p.setData(d)
+
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p PointZM) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("POINT(%g %g %g %g)", p.X, p.Y, p.Z, p.M), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *PointZM) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.M = 0
-		p.Valid = false
-		return nil
+func (p *PointZM) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXYZM)
+	if e != nil {
+		return e
 	}
 
-	type point PointZM
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p PointZM) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p PointZM) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p PointZM) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *PointZM) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXYZM)
+	if e != nil {
+		return e
 	}
 
-	p.X = tp.X
-	p.Y = tp.Y
-	p.Z = tp.Z
-	p.M = tp.M
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p PointZM) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p PointZM) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *PointZM) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXYZM)
+	if e != nil {
+		return e
 	}
 
-	type point PointZM
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
 
 // PointZMS (SRID X, Y, Z, M) datatype.
-// Supports NULL value.
+// Supports NULL and EMPTY values.
 type PointZMS struct {
 	SRID  uint32
 	X     float64
 	Y     float64
 	Z     float64
 	M     float64
+	Empty bool `json:",omitempty"`
 	Valid bool `json:"-"`
 }
 
+func (p PointZMS) data() pointData {
+	return pointData{
+		layout: layoutXYZMS,
+		srid:   p.SRID,
+		coords: [4]float64{p.X, p.Y, p.Z, p.M},
+		empty:  p.Empty,
+		valid:  p.Valid,
+	}
+}
+
+func (p *PointZMS) setData(d pointData) {
+	p.SRID = d.srid
+	p.X, p.Y, p.Z, p.M = d.coords[0], d.coords[1], d.coords[2], d.coords[3]
+	p.Empty = d.empty
+	p.Valid = d.valid
+}
+
 // Scan implements sql.Scanner
 func (p *PointZMS) Scan(src any) error {
-	if src == nil {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.M = 0
-		p.Valid = false
-		return nil
-	}
-
-	switch v := src.(type) {
-	case []byte:
-		d, e := NewHexEWKBDecoder(v)
-		if e != nil {
-			return e
-		}
-		srid, e := d.ReadUint32()
-		if e != nil {
-			return e
-		}
-		values := make([]float64, 4)
-		if e := d.ReadAny(values); e != nil {
-			return e
-		}
-		p.SRID = srid
-		p.X = values[0]
-		p.Y = values[1]
-		p.Z = values[2]
-		p.M = values[3]
-		p.Valid = true
-		return nil
-
-	default:
-		return fmt.Errorf("driver datatype not supported")
+	d, e := scanPoint(src, layoutXYZMS)
+	if e != nil {
+		return e
 	}
+
+	p.setData(d)
+
+	return nil
 }
 
 // Value implements driver.Valuer
 func (p PointZMS) Value() (driver.Value, error) {
-	if !p.Valid {
-		return nil, nil
-	}
-
-	return fmt.Sprintf("SRID=%d;POINT(%g %g %g %g)", p.SRID, p.X, p.Y, p.Z, p.M), nil
+	return p.data().value()
 }
 
 // UnmarshalJSON implements json.Unmarshaler
-func (p *PointZMS) UnmarshalJSON(d []byte) error {
-	if string(d) == jsonNullString {
-		p.SRID = 0
-		p.X = 0
-		p.Y = 0
-		p.Z = 0
-		p.M = 0
-		p.Valid = false
-		return nil
+func (p *PointZMS) UnmarshalJSON(b []byte) error {
+	d, e := unmarshalPointJSON(b, layoutXYZMS)
+	if e != nil {
+		return e
 	}
 
-	type point PointZMS
-	var tp point
+	p.setData(d)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (p PointZMS) MarshalJSON() ([]byte, error) {
+	return p.data().marshalJSON()
+}
 
-	if err := json.Unmarshal(d, &tp); err != nil {
-		return err
+// MarshalEWKB implements EWKBMarshaler
+func (p PointZMS) MarshalEWKB() ([]byte, error) {
+	return p.data().marshalEWKB()
+}
+
+// MarshalTWKB encodes p as TWKB. NULL is encoded as a nil slice.
+func (p PointZMS) MarshalTWKB(o TWKBOptions) ([]byte, error) {
+	return p.data().marshalTWKB(o)
+}
+
+// UnmarshalTWKB decodes TWKB point b into p. A nil slice decodes as NULL.
+func (p *PointZMS) UnmarshalTWKB(b []byte) error {
+	d, e := unmarshalTWKB(b, layoutXYZMS)
+	if e != nil {
+		return e
 	}
 
-	p.SRID = tp.SRID
-	p.X = tp.X
-	p.Y = tp.Y
-	p.Z = tp.Z
-	p.M = tp.M
-	p.Valid = true
+	p.setData(d)
 
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
-func (p PointZMS) MarshalJSON() ([]byte, error) {
-	if !p.Valid {
-		return jsonNullValue, nil
+// MarshalGPKG encodes p as GeoPackage binary. NULL is encoded as a nil slice.
+func (p PointZMS) MarshalGPKG() ([]byte, error) {
+	return p.data().marshalGPKG()
+}
+
+// UnmarshalGPKG decodes GeoPackage binary point b into p. A nil slice decodes as NULL.
+func (p *PointZMS) UnmarshalGPKG(b []byte) error {
+	d, e := unmarshalGPKG(b, layoutXYZMS)
+	if e != nil {
+		return e
 	}
 
-	type point PointZMS
-	tp := point(p)
+	p.setData(d)
 
-	return json.Marshal(tp)
+	return nil
 }
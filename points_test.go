@@ -3,6 +3,8 @@ package gopostgis_test
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 
 	gopostgis "github.com/asif-mahmud/go-postgis"
@@ -617,7 +619,7 @@ func TestPointM(t *testing.T) {
 			Valid: true,
 		}
 		found, e := p.Value()
-		expected := "POINT(10 20 30)"
+		expected := "POINT M(10 20 30)"
 		if e != nil {
 			t.Error(e)
 		}
@@ -762,7 +764,7 @@ func TestPointMS(t *testing.T) {
 			Valid: true,
 		}
 		found, e := p.Value()
-		expected := "SRID=4326;POINT(10 20 30)"
+		expected := "SRID=4326;POINT M(10 20 30)"
 		if e != nil {
 			t.Error(e)
 		}
@@ -1174,3 +1176,267 @@ func TestPointZMS(t *testing.T) {
 		}
 	})
 }
+
+func TestNonFinite(t *testing.T) {
+	t.Run("value nan", func(t *testing.T) {
+		p := gopostgis.PointZ{
+			X:     1,
+			Y:     2,
+			Z:     math.NaN(),
+			Valid: true,
+		}
+		if _, e := p.Value(); !errors.Is(e, gopostgis.ErrNonFiniteCoordinate) {
+			t.Error("expected:", gopostgis.ErrNonFiniteCoordinate, "found:", e)
+		}
+	})
+
+	t.Run("value inf", func(t *testing.T) {
+		p := gopostgis.PointS{
+			SRID:  4326,
+			X:     math.Inf(1),
+			Y:     2,
+			Valid: true,
+		}
+		if _, e := p.Value(); !errors.Is(e, gopostgis.ErrNonFiniteCoordinate) {
+			t.Error("expected:", gopostgis.ErrNonFiniteCoordinate, "found:", e)
+		}
+	})
+
+	t.Run("value empty", func(t *testing.T) {
+		p := gopostgis.PointZMS{
+			SRID:  4326,
+			X:     math.NaN(),
+			Y:     math.NaN(),
+			Z:     math.NaN(),
+			M:     math.NaN(),
+			Valid: true,
+		}
+		found, e := p.Value()
+		expected := "SRID=4326;POINT ZM EMPTY"
+		if e != nil {
+			t.Error(e)
+		}
+		if expected != found {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("unmarshal overflow", func(t *testing.T) {
+		var p gopostgis.PointM
+		s := `{"X":10,"Y":20,"M":1e400}`
+		if e := json.Unmarshal([]byte(s), &p); !errors.Is(e, gopostgis.ErrNonFiniteCoordinate) {
+			t.Error("expected:", gopostgis.ErrNonFiniteCoordinate, "found:", e)
+		}
+	})
+
+	t.Run("unmarshal srid overflow", func(t *testing.T) {
+		var p gopostgis.PointS
+		s := `{"SRID":-1,"X":10,"Y":20}`
+		e := json.Unmarshal([]byte(s), &p)
+		if e == nil || errors.Is(e, gopostgis.ErrNonFiniteCoordinate) {
+			t.Error("expected a type error, found:", e)
+		}
+	})
+}
+
+func TestEmpty(t *testing.T) {
+	t.Run("scan nan point", func(t *testing.T) {
+		// POINT EMPTY
+		s := "0101000000000000000000F87F000000000000F87F"
+		var p gopostgis.Point
+		e := p.Scan([]byte(s))
+		if e != nil {
+			t.Error(e)
+		}
+		if !p.Valid || !p.Empty || p.X != 0 || p.Y != 0 {
+			t.Error("expected empty point, found:", p)
+		}
+	})
+
+	t.Run("scan srid nan point", func(t *testing.T) {
+		// SRID=4326;POINT EMPTY
+		s := "0101000020E6100000000000000000F87F000000000000F87F"
+		var p gopostgis.PointS
+		e := p.Scan([]byte(s))
+		if e != nil {
+			t.Error(e)
+		}
+		if !p.Valid || !p.Empty || p.SRID != 4326 {
+			t.Error("expected empty point, found:", p)
+		}
+	})
+
+	t.Run("scan empty collection", func(t *testing.T) {
+		// GEOMETRYCOLLECTION EMPTY
+		s := "010700000000000000"
+		var p gopostgis.PointZ
+		e := p.Scan([]byte(s))
+		if e != nil {
+			t.Error(e)
+		}
+		if !p.Valid || !p.Empty {
+			t.Error("expected empty point, found:", p)
+		}
+	})
+
+	t.Run("scan non empty collection", func(t *testing.T) {
+		// MULTIPOINT(10 20)
+		s := "010400000001000000010100000000000000000024400000000000003440"
+		var p gopostgis.Point
+		if e := p.Scan([]byte(s)); e == nil {
+			t.Error("multipoint should not be scanned into a point")
+		}
+	})
+
+	t.Run("value", func(t *testing.T) {
+		p := gopostgis.PointM{
+			Empty: true,
+			Valid: true,
+		}
+		found, e := p.Value()
+		expected := "POINT M EMPTY"
+		if e != nil {
+			t.Error(e)
+		}
+		if expected != found {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("value srid", func(t *testing.T) {
+		p := gopostgis.PointZS{
+			SRID:  4326,
+			Empty: true,
+			Valid: true,
+		}
+		found, e := p.Value()
+		expected := "SRID=4326;POINT Z EMPTY"
+		if e != nil {
+			t.Error(e)
+		}
+		if expected != found {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("value null", func(t *testing.T) {
+		p := gopostgis.Point{
+			Empty: true,
+			Valid: false,
+		}
+		found, e := p.Value()
+		if e != nil {
+			t.Error(e)
+		}
+		if found != nil {
+			t.Error("expected: nil found:", found)
+		}
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		p := gopostgis.PointZM{
+			Empty: true,
+			Valid: true,
+		}
+		expected := `{"Empty":true}`
+		found, e := p.MarshalJSON()
+		if e != nil {
+			t.Error(e)
+		}
+		if expected != string(found) {
+			t.Error("expected:", expected, "found:", string(found))
+		}
+	})
+
+	t.Run("marshal srid", func(t *testing.T) {
+		p := gopostgis.PointMS{
+			SRID:  4326,
+			Empty: true,
+			Valid: true,
+		}
+		expected := `{"SRID":4326,"Empty":true}`
+		found, e := p.MarshalJSON()
+		if e != nil {
+			t.Error(e)
+		}
+		if expected != string(found) {
+			t.Error("expected:", expected, "found:", string(found))
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var p gopostgis.PointZMS
+		s := `{"SRID":4326,"Empty":true}`
+		if e := json.Unmarshal([]byte(s), &p); e != nil {
+			t.Error(e)
+		}
+		if !p.Valid || !p.Empty || p.SRID != 4326 {
+			t.Error("expected empty point, found:", p)
+		}
+	})
+}
+
+func TestPointJSONFormat(t *testing.T) {
+	values := []float64{0, -0.5, 1e-7, 123456789.125, 1e21, -3.5e-10}
+	for _, v := range values {
+		p := gopostgis.PointZS{
+			SRID:  4326,
+			X:     v,
+			Y:     -v,
+			Z:     v * 2,
+			Valid: true,
+		}
+		expected, e := json.Marshal(struct {
+			SRID    uint32
+			X, Y, Z float64
+		}{p.SRID, p.X, p.Y, p.Z})
+		if e != nil {
+			t.Error(e)
+		}
+		found, e := p.MarshalJSON()
+		if e != nil {
+			t.Error(e)
+		}
+		if string(expected) != string(found) {
+			t.Error("expected:", string(expected), "found:", string(found))
+		}
+	}
+}
+
+func TestPointScanAllocs(t *testing.T) {
+	// SRID=4326;POINT(10 20 30 40)
+	var src any = []byte("01010000E0E6100000000000000000244000000000000034400000000000003E400000000000004440")
+	var p gopostgis.PointZMS
+	allocs := testing.AllocsPerRun(100, func() {
+		if e := p.Scan(src); e != nil {
+			t.Error(e)
+		}
+	})
+	if allocs != 0 {
+		t.Error("expected: 0 allocations found:", allocs)
+	}
+}
+
+func BenchmarkPointScan(b *testing.B) {
+	// POINT(10 20)
+	var src any = []byte("010100000000000000000024400000000000003440")
+	var p gopostgis.Point
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if e := p.Scan(src); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
+
+func BenchmarkPointZMSScan(b *testing.B) {
+	// SRID=4326;POINT(10 20 30 40)
+	var src any = []byte("01010000E0E6100000000000000000244000000000000034400000000000003E400000000000004440")
+	var p gopostgis.PointZMS
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if e := p.Scan(src); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
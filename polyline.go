@@ -0,0 +1,157 @@
+package gopostgis
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Google encoded polyline algorithm.
+// reference - https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+// Polylines store latitude before longitude, i.e. Y before X, and are
+// always in SRID 4326.
+
+// Common precisions of encoded polylines. Google uses 5, OSRM and
+// valhalla use 6.
+const (
+	PolylinePrecision5 = 5
+	PolylinePrecision6 = 6
+)
+
+var errPolylineInvalid = errors.New("invalid encoded polyline")
+
+// PolylinePoint is the constraint of point types usable with polylines.
+type PolylinePoint interface {
+	Point | PointS
+	data() pointData
+}
+
+func checkPolylinePrecision(precision int) error {
+	if precision < 1 || precision > 10 {
+		return fmt.Errorf("polyline precision out of range. got: %d", precision)
+	}
+
+	return nil
+}
+
+// EncodePolyline encodes points as an encoded polyline with precision
+// decimal digits. Points must not be NULL or EMPTY, and PointS must have
+// SRID 4326.
+func EncodePolyline[T PolylinePoint](points []T, precision int) (string, error) {
+	if err := checkPolylinePrecision(precision); err != nil {
+		return "", err
+	}
+
+	scale := math.Pow10(precision)
+
+	var b strings.Builder
+	var prevLat, prevLng int64
+	for i, p := range points {
+		d := p.data()
+		empty, err := d.check()
+		if err != nil {
+			return "", fmt.Errorf("point %d: %w", i, err)
+		}
+		if !d.valid || empty {
+			return "", fmt.Errorf("point %d: polyline can not have NULL or EMPTY points", i)
+		}
+		if d.layout.srid && d.srid != 4326 {
+			return "", fmt.Errorf("point %d: polyline srid must be 4326. got: %d", i, d.srid)
+		}
+
+		lat := int64(math.Round(d.coords[1] * scale))
+		lng := int64(math.Round(d.coords[0] * scale))
+		appendPolylineValue(&b, lat-prevLat)
+		appendPolylineValue(&b, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+
+	return b.String(), nil
+}
+
+func appendPolylineValue(b *strings.Builder, v int64) {
+	u := uint64(v) << 1
+	if v < 0 {
+		u = ^u
+	}
+
+	for u >= 0x20 {
+		b.WriteByte(byte(0x20|u&0x1f) + 63)
+		u >>= 5
+	}
+	b.WriteByte(byte(u) + 63)
+}
+
+// DecodePolyline decodes an encoded polyline with precision decimal
+// digits into points. Decoded PointS have SRID 4326.
+func DecodePolyline[T PolylinePoint, P interface {
+	*T
+	setData(pointData)
+}](s string, precision int) ([]T, error) {
+	if err := checkPolylinePrecision(precision); err != nil {
+		return nil, err
+	}
+
+	var zero T
+	layout := zero.data().layout
+	scale := math.Pow10(precision)
+
+	var points []T
+	var lat, lng int64
+	for i := 0; i < len(s); {
+		dlat, n, err := readPolylineValue(s[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+
+		dlng, n, err := readPolylineValue(s[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+
+		lat += dlat
+		lng += dlng
+
+		d := pointData{
+			layout: layout,
+			coords: [4]float64{float64(lng) / scale, float64(lat) / scale},
+			valid:  true,
+		}
+		if layout.srid {
+			d.srid = 4326
+		}
+
+		var p T
+		P(&p).setData(d)
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
+func readPolylineValue(s string) (int64, int, error) {
+	var u uint64
+	var shift uint
+	for i := 0; i < len(s); i++ {
+		c := int(s[i]) - 63
+		if c < 0 || c > 0x3f || shift > 60 {
+			return 0, 0, errPolylineInvalid
+		}
+
+		u |= uint64(c&0x1f) << shift
+		shift += 5
+
+		if c < 0x20 {
+			v := int64(u >> 1)
+			if u&1 != 0 {
+				v = ^v
+			}
+			return v, i + 1, nil
+		}
+	}
+
+	return 0, 0, errPolylineInvalid
+}
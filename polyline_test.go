@@ -0,0 +1,81 @@
+package gopostgis_test
+
+import (
+	"math"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestPolyline(t *testing.T) {
+	// example from the polyline algorithm documentation
+	const encoded = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+	points := []gopostgis.Point{
+		{X: -120.2, Y: 38.5, Valid: true},
+		{X: -120.95, Y: 40.7, Valid: true},
+		{X: -126.453, Y: 43.252, Valid: true},
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		found, e := gopostgis.EncodePolyline(points, gopostgis.PolylinePrecision5)
+		if e != nil {
+			t.Error(e)
+		}
+		if found != encoded {
+			t.Error("expected:", encoded, "found:", found)
+		}
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		found, e := gopostgis.DecodePolyline[gopostgis.Point](encoded, gopostgis.PolylinePrecision5)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(found) != len(points) {
+			t.Fatal("expected:", points, "found:", found)
+		}
+		for i := range points {
+			if math.Abs(found[i].X-points[i].X) > 1e-9 || math.Abs(found[i].Y-points[i].Y) > 1e-9 || !found[i].Valid {
+				t.Error("expected:", points[i], "found:", found[i])
+			}
+		}
+	})
+
+	t.Run("precision 6", func(t *testing.T) {
+		in := []gopostgis.PointS{
+			{SRID: 4326, X: 13.388860, Y: 52.517037, Valid: true},
+			{SRID: 4326, X: 13.397634, Y: 52.529407, Valid: true},
+		}
+		s, e := gopostgis.EncodePolyline(in, gopostgis.PolylinePrecision6)
+		if e != nil {
+			t.Fatal(e)
+		}
+		found, e := gopostgis.DecodePolyline[gopostgis.PointS](s, gopostgis.PolylinePrecision6)
+		if e != nil {
+			t.Fatal(e)
+		}
+		for i := range in {
+			if found[i].SRID != 4326 || math.Abs(found[i].X-in[i].X) > 1e-9 || math.Abs(found[i].Y-in[i].Y) > 1e-9 {
+				t.Error("expected:", in[i], "found:", found[i])
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, e := gopostgis.EncodePolyline([]gopostgis.PointS{{SRID: 3857, Valid: true}}, 5); e == nil {
+			t.Error("srid 3857 should fail")
+		}
+		if _, e := gopostgis.EncodePolyline([]gopostgis.Point{{Valid: false}}, 5); e == nil {
+			t.Error("null point should fail")
+		}
+		if _, e := gopostgis.EncodePolyline(points, 0); e == nil {
+			t.Error("precision 0 should fail")
+		}
+		if _, e := gopostgis.DecodePolyline[gopostgis.Point]("_p~iF~ps|", 5); e == nil {
+			t.Error("truncated polyline should fail")
+		}
+		if _, e := gopostgis.DecodePolyline[gopostgis.Point]("_p~iF ps|U", 5); e == nil {
+			t.Error("invalid character should fail")
+		}
+	})
+}
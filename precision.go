@@ -0,0 +1,141 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// Grid is a grid coordinates are snapped to, same as the parameters of
+// ST_SnapToGrid. Every coordinate is rounded to the nearest cell corner
+// counted from the origin, a zero size leaves the coordinate unchanged.
+type Grid struct {
+	OriginX float64
+	OriginY float64
+	OriginZ float64
+	OriginM float64
+	SizeX   float64
+	SizeY   float64
+	SizeZ   float64
+	SizeM   float64
+}
+
+// NewGrid returns a grid of size for X and Y with the origin at 0, 0, same
+// as ST_SnapToGrid(geom, size) and ST_ReducePrecision(geom, size).
+func NewGrid(size float64) Grid {
+	return Grid{SizeX: size, SizeY: size}
+}
+
+// Snap snaps geometry geom to the grid in place. geom is a pointer to a
+// point type, or a slice, possibly nested, of point types. NULL and EMPTY
+// points are left unchanged, and repeated points are kept.
+func (g Grid) Snap(geom any) error {
+	if err := g.check(); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(geom)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return applyPoints(v, g.snap)
+}
+
+// SnapToGrid returns a copy of geometry g snapped to grid, g is any point
+// type or a slice, possibly nested, of point types.
+func SnapToGrid[G any](g G, grid Grid) (G, error) {
+	var zero G
+	if err := grid.check(); err != nil {
+		return zero, err
+	}
+
+	v := reflect.ValueOf(&g).Elem()
+	c := reflect.New(v.Type()).Elem()
+	c.Set(deepCopy(v))
+
+	if err := applyPoints(c, grid.snap); err != nil {
+		return zero, err
+	}
+
+	return c.Interface().(G), nil
+}
+
+func (g Grid) check() error {
+	for _, v := range [...]float64{g.OriginX, g.OriginY, g.OriginZ, g.OriginM, g.SizeX, g.SizeY, g.SizeZ, g.SizeM} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: grid %v", ErrNonFiniteCoordinate, g)
+		}
+	}
+
+	if g.SizeX < 0 || g.SizeY < 0 || g.SizeZ < 0 || g.SizeM < 0 {
+		return fmt.Errorf("grid size can not be negative. got: %v", g)
+	}
+
+	return nil
+}
+
+func (g Grid) snap(d pointData) pointData {
+	if !d.valid || d.empty {
+		return d
+	}
+
+	d.coords[0] = snapCoord(d.coords[0], g.OriginX, g.SizeX)
+	d.coords[1] = snapCoord(d.coords[1], g.OriginY, g.SizeY)
+	for i := 2; i < d.layout.dims(); i++ {
+		if d.layout.names[i] == "Z" {
+			d.coords[i] = snapCoord(d.coords[i], g.OriginZ, g.SizeZ)
+		} else {
+			d.coords[i] = snapCoord(d.coords[i], g.OriginM, g.SizeM)
+		}
+	}
+
+	return d
+}
+
+// Rounds half to even like rint in postgis.
+func snapCoord(v, origin, size float64) float64 {
+	if size == 0 {
+		return v
+	}
+
+	return math.RoundToEven((v-origin)/size)*size + origin
+}
+
+var precision struct {
+	mu   sync.RWMutex
+	grid *Grid
+}
+
+// SetPrecision sets the grid every point type snaps coordinates to before
+// writing them with Value, MarshalEWKB, MarshalGPKG or MarshalTWKB, nil
+// disables snapping. Snapping is disabled by default.
+func SetPrecision(g *Grid) error {
+	if g != nil {
+		if err := g.check(); err != nil {
+			return err
+		}
+		c := *g
+		g = &c
+	}
+
+	precision.mu.Lock()
+	defer precision.mu.Unlock()
+
+	precision.grid = g
+
+	return nil
+}
+
+// Snaps d to the grid set by SetPrecision.
+func (d pointData) withPrecision() pointData {
+	precision.mu.RLock()
+	defer precision.mu.RUnlock()
+
+	if precision.grid == nil {
+		return d
+	}
+
+	return precision.grid.snap(d)
+}
@@ -0,0 +1,179 @@
+package gopostgis_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestGrid(t *testing.T) {
+	t.Run("snap to grid", func(t *testing.T) {
+		p := gopostgis.PointZM{X: 1.234, Y: -5.678, Z: 12.3, M: 0.26, Valid: true}
+		cases := []struct {
+			name     string
+			grid     gopostgis.Grid
+			expected gopostgis.PointZM
+		}{
+			{"size", gopostgis.NewGrid(0.01), gopostgis.PointZM{X: 1.23, Y: -5.68, Z: 12.3, M: 0.26, Valid: true}},
+			{"per axis", gopostgis.Grid{SizeX: 1, SizeY: 0.5, SizeZ: 10, SizeM: 0.1}, gopostgis.PointZM{X: 1, Y: -5.5, Z: 10, M: 0.30000000000000004, Valid: true}},
+			{"origin", gopostgis.Grid{OriginX: 0.5, OriginY: 0.25, SizeX: 1, SizeY: 1}, gopostgis.PointZM{X: 1.5, Y: -5.75, Z: 12.3, M: 0.26, Valid: true}},
+			{"half to even", gopostgis.Grid{OriginX: 0.234, SizeX: 2}, gopostgis.PointZM{X: 0.234, Y: -5.678, Z: 12.3, M: 0.26, Valid: true}},
+			{"zero grid", gopostgis.Grid{}, p},
+		}
+
+		for _, c := range cases {
+			found, err := gopostgis.SnapToGrid(p, c.grid)
+			if err != nil {
+				t.Fatal("expected: nil found:", err)
+			}
+			if found != c.expected {
+				t.Error(c.name, "expected:", c.expected, "found:", found)
+			}
+		}
+	})
+
+	t.Run("in place", func(t *testing.T) {
+		polygon := [][]gopostgis.PointS{{
+			{SRID: 4326, X: 0.1, Y: 0.1, Valid: true},
+			{SRID: 4326, X: 1.9, Y: 0.2, Valid: true},
+			{SRID: 4326},
+		}}
+		if err := gopostgis.NewGrid(1).Snap(polygon); err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		expected := [][]gopostgis.PointS{{
+			{SRID: 4326, X: 0, Y: 0, Valid: true},
+			{SRID: 4326, X: 2, Y: 0, Valid: true},
+			{SRID: 4326},
+		}}
+		if !reflect.DeepEqual(polygon, expected) {
+			t.Error("expected:", expected, "found:", polygon)
+		}
+
+		p := gopostgis.PointM{X: 2.6, Y: 3.4, M: 1.5, Valid: true}
+		if err := gopostgis.NewGrid(1).Snap(&p); err != nil {
+			t.Fatal("expected: nil found:", err)
+		}
+		if e := (gopostgis.PointM{X: 3, Y: 3, M: 1.5, Valid: true}); p != e {
+			t.Error("expected:", e, "found:", p)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if err := gopostgis.NewGrid(-1).Snap([]gopostgis.Point{}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+		if _, err := gopostgis.SnapToGrid(gopostgis.Point{}, gopostgis.NewGrid(math.Inf(1))); !errors.Is(err, gopostgis.ErrNonFiniteCoordinate) {
+			t.Error("expected:", gopostgis.ErrNonFiniteCoordinate, "found:", err)
+		}
+		if err := gopostgis.SetPrecision(&gopostgis.Grid{SizeZ: -2}); err == nil {
+			t.Error("expected: error found:", err)
+		}
+	})
+}
+
+func TestPrecision(t *testing.T) {
+	defer gopostgis.SetPrecision(nil)
+
+	p := gopostgis.PointZS{SRID: 4326, X: 90.1234567, Y: 23.7654321, Z: 12.345, Valid: true}
+
+	grid := gopostgis.NewGrid(0.0001)
+	if err := gopostgis.SetPrecision(&grid); err != nil {
+		t.Fatal("expected: nil found:", err)
+	}
+	// later changes to grid have no effect
+	grid.SizeX = 1
+
+	v, err := p.Value()
+	if expected := "SRID=4326;POINT(90.1235 23.7654 12.345)"; err != nil || v != expected {
+		t.Error("expected:", expected, "found:", v, err)
+	}
+	if p.X != 90.1234567 {
+		t.Error("expected:", 90.1234567, "found:", p.X)
+	}
+
+	empty := gopostgis.PointZS{SRID: 4326, Empty: true, Valid: true}
+	if v, err := empty.Value(); v != "SRID=4326;POINT Z EMPTY" || err != nil {
+		t.Error("expected:", "SRID=4326;POINT Z EMPTY", "found:", v, err)
+	}
+
+	gopostgis.SetPrecision(nil)
+	v, err = p.Value()
+	if expected := "SRID=4326;POINT(90.1234567 23.7654321 12.345)"; err != nil || v != expected {
+		t.Error("expected:", expected, "found:", v, err)
+	}
+}
+
+func TestPrecisionWriters(t *testing.T) {
+	defer gopostgis.SetPrecision(nil)
+
+	grid := gopostgis.NewGrid(0.5)
+	if err := gopostgis.SetPrecision(&grid); err != nil {
+		t.Fatal("expected: nil found:", err)
+	}
+
+	p := gopostgis.PointS{SRID: 4326, X: 1.3, Y: 1.8, Valid: true}
+	expected := gopostgis.PointS{SRID: 4326, X: 1.5, Y: 2, Valid: true}
+
+	t.Run("copy text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := gopostgis.NewCopyTextWriter(&buf).WriteRow(p); err != nil {
+			t.Fatal(err)
+		}
+
+		var found gopostgis.PointS
+		if err := found.Scan(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))); err != nil || found != expected {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("copy binary", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := gopostgis.NewCopyBinaryWriter(&buf)
+		if err := w.WriteRow(p); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := readCopyBinary(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var found gopostgis.PointS
+		if err := found.Scan([]byte(hex.EncodeToString(rows[0][0]))); err != nil || found != expected {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("gpkg", func(t *testing.T) {
+		b, err := p.MarshalGPKG()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var found gopostgis.PointS
+		if err := found.UnmarshalGPKG(b); err != nil || found != expected {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+
+	t.Run("twkb", func(t *testing.T) {
+		b, err := p.MarshalTWKB(gopostgis.TWKBOptions{Precision: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var found gopostgis.PointS
+		if err := found.UnmarshalTWKB(b); err != nil || found.X != 1.5 || found.Y != 2 {
+			t.Error("expected:", expected, "found:", found, err)
+		}
+	})
+}
@@ -0,0 +1,449 @@
+package gopostgis
+
+import (
+	"container/heap"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// In memory R-tree over values keyed on their envelopes.
+// reference - https://en.wikipedia.org/wiki/R-tree
+
+const (
+	rtreeMaxEntries = 16
+	rtreeMinEntries = 6
+)
+
+type rtreeEntry[T any] struct {
+	env   Envelope
+	child *rtreeNode[T]
+	value T
+}
+
+type rtreeNode[T any] struct {
+	leaf    bool
+	entries []rtreeEntry[T]
+}
+
+func (n *rtreeNode[T]) envelope() Envelope {
+	e := n.entries[0].env
+	for _, c := range n.entries[1:] {
+		e = e.Union(c.env)
+	}
+
+	return e
+}
+
+// RTree is a spatial index over values of type T, which must be
+// accepted by [EnvelopeOf]. It is not safe for concurrent writes.
+type RTree[T any] struct {
+	root *rtreeNode[T]
+	size int
+}
+
+// NewRTree returns an R-tree bulk loaded with values using the
+// Sort-Tile-Recursive algorithm.
+func NewRTree[T any](values ...T) (*RTree[T], error) {
+	entries := make([]rtreeEntry[T], len(values))
+	for i, v := range values {
+		env, err := EnvelopeOf(v)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = rtreeEntry[T]{env: env, value: v}
+	}
+
+	return bulkLoad(entries), nil
+}
+
+// Builds an R-tree over leaf entries with Sort-Tile-Recursive.
+func bulkLoad[T any](entries []rtreeEntry[T]) *RTree[T] {
+	t := &RTree[T]{size: len(entries)}
+	if len(entries) == 0 {
+		return t
+	}
+
+	leaf := true
+	for {
+		nodes := strPack(entries, leaf)
+		if len(nodes) == 1 {
+			t.root = nodes[0]
+			return t
+		}
+
+		entries = make([]rtreeEntry[T], len(nodes))
+		for i, n := range nodes {
+			entries[i] = rtreeEntry[T]{env: n.envelope(), child: n}
+		}
+		leaf = false
+	}
+}
+
+// Packs entries into nodes, sorting them into vertical slices by x
+// and then within each slice by y.
+func strPack[T any](entries []rtreeEntry[T], leaf bool) []*rtreeNode[T] {
+	centerX := func(e rtreeEntry[T]) float64 { return e.env.MinX + e.env.MaxX }
+	centerY := func(e rtreeEntry[T]) float64 { return e.env.MinY + e.env.MaxY }
+
+	leaves := (len(entries) + rtreeMaxEntries - 1) / rtreeMaxEntries
+	slices := int(math.Ceil(math.Sqrt(float64(leaves))))
+	sliceSize := slices * rtreeMaxEntries
+
+	sort.Slice(entries, func(i, j int) bool { return centerX(entries[i]) < centerX(entries[j]) })
+
+	var nodes []*rtreeNode[T]
+	for s := 0; s < len(entries); s += sliceSize {
+		slice := entries[s:minInt(s+sliceSize, len(entries))]
+		sort.Slice(slice, func(i, j int) bool { return centerY(slice[i]) < centerY(slice[j]) })
+
+		for n := 0; n < len(slice); n += rtreeMaxEntries {
+			node := &rtreeNode[T]{leaf: leaf}
+			node.entries = append(node.entries, slice[n:minInt(n+rtreeMaxEntries, len(slice))]...)
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// Len returns the number of values in t.
+func (t *RTree[T]) Len() int {
+	return t.size
+}
+
+// Insert adds v to t.
+func (t *RTree[T]) Insert(v T) error {
+	env, err := EnvelopeOf(v)
+	if err != nil {
+		return err
+	}
+
+	t.insert(rtreeEntry[T]{env: env, value: v})
+	t.size++
+
+	return nil
+}
+
+func (t *RTree[T]) insert(e rtreeEntry[T]) {
+	if t.root == nil {
+		t.root = &rtreeNode[T]{leaf: true}
+	}
+
+	split := t.root.insert(e)
+	if split != nil {
+		old := t.root
+		t.root = &rtreeNode[T]{
+			entries: []rtreeEntry[T]{
+				{env: old.envelope(), child: old},
+				{env: split.envelope(), child: split},
+			},
+		}
+	}
+}
+
+// Inserts e into the subtree of n, returns the new sibling if n was split.
+func (n *rtreeNode[T]) insert(e rtreeEntry[T]) *rtreeNode[T] {
+	if !n.leaf {
+		i := n.chooseSubtree(e.env)
+		c := &n.entries[i]
+		split := c.child.insert(e)
+		c.env = c.env.Union(e.env)
+		if split != nil {
+			c.env = c.child.envelope()
+			n.entries = append(n.entries, rtreeEntry[T]{env: split.envelope(), child: split})
+		}
+	} else {
+		n.entries = append(n.entries, e)
+	}
+
+	if len(n.entries) > rtreeMaxEntries {
+		return n.split()
+	}
+
+	return nil
+}
+
+// Index of the entry needing least enlargement to include env,
+// ties are resolved by the smallest area.
+func (n *rtreeNode[T]) chooseSubtree(env Envelope) int {
+	best := 0
+	bestEnlargement, bestArea := math.Inf(1), math.Inf(1)
+	for i, c := range n.entries {
+		area := c.env.Area()
+		enlargement := c.env.Union(env).Area() - area
+		if enlargement < bestEnlargement || (enlargement == bestEnlargement && area < bestArea) {
+			best, bestEnlargement, bestArea = i, enlargement, area
+		}
+	}
+
+	return best
+}
+
+// Splits n with the quadratic algorithm, n keeps one group and the
+// other one is returned as a new node.
+func (n *rtreeNode[T]) split() *rtreeNode[T] {
+	entries := n.entries
+
+	// seeds wasting the most area together
+	s1, s2, worst := 0, 1, math.Inf(-1)
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			d := entries[i].env.Union(entries[j].env).Area() - entries[i].env.Area() - entries[j].env.Area()
+			if d > worst {
+				s1, s2, worst = i, j, d
+			}
+		}
+	}
+
+	g1 := []rtreeEntry[T]{entries[s1]}
+	g2 := []rtreeEntry[T]{entries[s2]}
+	e1, e2 := entries[s1].env, entries[s2].env
+
+	rest := make([]rtreeEntry[T], 0, len(entries)-2)
+	for i, e := range entries {
+		if i != s1 && i != s2 {
+			rest = append(rest, e)
+		}
+	}
+
+	for len(rest) > 0 {
+		if len(g1)+len(rest) == rtreeMinEntries {
+			g1 = append(g1, rest...)
+			break
+		}
+		if len(g2)+len(rest) == rtreeMinEntries {
+			g2 = append(g2, rest...)
+			break
+		}
+
+		// entry with the strongest preference for a group
+		next, diff := 0, math.Inf(-1)
+		for i, e := range rest {
+			d1 := e1.Union(e.env).Area() - e1.Area()
+			d2 := e2.Union(e.env).Area() - e2.Area()
+			if d := math.Abs(d1 - d2); d > diff {
+				next, diff = i, d
+			}
+		}
+
+		e := rest[next]
+		rest = append(rest[:next], rest[next+1:]...)
+
+		d1 := e1.Union(e.env).Area() - e1.Area()
+		d2 := e2.Union(e.env).Area() - e2.Area()
+		first := d1 < d2 ||
+			(d1 == d2 && e1.Area() < e2.Area()) ||
+			(d1 == d2 && e1.Area() == e2.Area() && len(g1) <= len(g2))
+		if first {
+			g1 = append(g1, e)
+			e1 = e1.Union(e.env)
+		} else {
+			g2 = append(g2, e)
+			e2 = e2.Union(e.env)
+		}
+	}
+
+	n.entries = g1
+
+	return &rtreeNode[T]{leaf: n.leaf, entries: g2}
+}
+
+// Delete removes a value equal to v from t, compared with
+// reflect.DeepEqual. It reports whether a value was removed.
+func (t *RTree[T]) Delete(v T) bool {
+	if t.root == nil {
+		return false
+	}
+
+	env, err := EnvelopeOf(v)
+	if err != nil {
+		return false
+	}
+
+	var orphans []rtreeEntry[T]
+	if !t.root.delete(env, v, &orphans) {
+		return false
+	}
+	t.size--
+
+	for len(t.root.entries) == 1 && !t.root.leaf {
+		t.root = t.root.entries[0].child
+	}
+	if len(t.root.entries) == 0 {
+		t.root = nil
+	}
+
+	for _, e := range orphans {
+		t.insert(e)
+	}
+
+	return true
+}
+
+// Removes v from the subtree of n. Children left with too few entries
+// are dropped and their values collected in orphans for reinsertion.
+func (n *rtreeNode[T]) delete(env Envelope, v T, orphans *[]rtreeEntry[T]) bool {
+	for i := range n.entries {
+		c := &n.entries[i]
+		if !c.env.Contains(env) {
+			continue
+		}
+
+		if n.leaf {
+			if reflect.DeepEqual(c.value, v) {
+				n.entries = append(n.entries[:i], n.entries[i+1:]...)
+				return true
+			}
+			continue
+		}
+
+		if !c.child.delete(env, v, orphans) {
+			continue
+		}
+
+		if len(c.child.entries) < rtreeMinEntries {
+			c.child.collect(orphans)
+			n.entries = append(n.entries[:i], n.entries[i+1:]...)
+		} else {
+			c.env = c.child.envelope()
+		}
+		return true
+	}
+
+	return false
+}
+
+// Appends the leaf entries of the subtree of n to entries.
+func (n *rtreeNode[T]) collect(entries *[]rtreeEntry[T]) {
+	if n.leaf {
+		*entries = append(*entries, n.entries...)
+		return
+	}
+
+	for _, c := range n.entries {
+		c.child.collect(entries)
+	}
+}
+
+// Search returns the values whose envelope intersects env.
+func (t *RTree[T]) Search(env Envelope) []T {
+	var values []T
+	t.visit(env, func(e rtreeEntry[T]) {
+		values = append(values, e.value)
+	})
+
+	return values
+}
+
+// Range returns the values whose envelope lies completely inside env.
+func (t *RTree[T]) Range(env Envelope) []T {
+	var values []T
+	t.visit(env, func(e rtreeEntry[T]) {
+		if env.Contains(e.env) {
+			values = append(values, e.value)
+		}
+	})
+
+	return values
+}
+
+// Intersects returns the values whose envelope intersects the envelope
+// of geometry g, same as filtering with the postgis && operator.
+func (t *RTree[T]) Intersects(g any) ([]T, error) {
+	env, err := EnvelopeOf(g)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Search(env), nil
+}
+
+// Calls fn with every leaf entry intersecting env.
+func (t *RTree[T]) visit(env Envelope, fn func(rtreeEntry[T])) {
+	if t.root == nil {
+		return
+	}
+
+	stack := []*rtreeNode[T]{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, e := range n.entries {
+			if !env.Intersects(e.env) {
+				continue
+			}
+			if n.leaf {
+				fn(e)
+			} else {
+				stack = append(stack, e.child)
+			}
+		}
+	}
+}
+
+// Nearest returns at most k values closest to point x, y ordered by the
+// planar distance of their envelopes, which is exact for points.
+func (t *RTree[T]) Nearest(x, y float64, k int) []T {
+	var values []T
+	t.nearest(k, func(e Envelope) float64 {
+		return e.Distance(x, y)
+	}, func(v T, _ float64) {
+		values = append(values, v)
+	})
+
+	return values
+}
+
+type rtreeItem[T any] struct {
+	entry rtreeEntry[T]
+	dist  float64
+}
+
+type rtreeQueue[T any] []rtreeItem[T]
+
+func (q rtreeQueue[T]) Len() int            { return len(q) }
+func (q rtreeQueue[T]) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q rtreeQueue[T]) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *rtreeQueue[T]) Push(x interface{}) { *q = append(*q, x.(rtreeItem[T])) }
+func (q *rtreeQueue[T]) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// Best first search calling fn with at most k values in the order of
+// dist, which must never overestimate the distance of a node.
+func (t *RTree[T]) nearest(k int, dist func(Envelope) float64, fn func(T, float64)) {
+	if t.root == nil || k <= 0 {
+		return
+	}
+
+	q := &rtreeQueue[T]{}
+	for _, e := range t.root.entries {
+		heap.Push(q, rtreeItem[T]{entry: e, dist: dist(e.env)})
+	}
+
+	for q.Len() > 0 && k > 0 {
+		item := heap.Pop(q).(rtreeItem[T])
+		if item.entry.child == nil {
+			fn(item.entry.value, item.dist)
+			k--
+			continue
+		}
+
+		for _, e := range item.entry.child.entries {
+			heap.Push(q, rtreeItem[T]{entry: e, dist: dist(e.env)})
+		}
+	}
+}
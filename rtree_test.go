@@ -0,0 +1,176 @@
+package gopostgis_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func randomPoints(n int, seed int64) []gopostgis.PointS {
+	r := rand.New(rand.NewSource(seed))
+	points := make([]gopostgis.PointS, n)
+	for i := range points {
+		points[i] = gopostgis.PointS{SRID: 4326, X: r.Float64()*360 - 180, Y: r.Float64()*180 - 90, Valid: true}
+	}
+	return points
+}
+
+func bruteSearch(points []gopostgis.PointS, env gopostgis.Envelope) map[gopostgis.PointS]bool {
+	found := map[gopostgis.PointS]bool{}
+	for _, p := range points {
+		if p.X >= env.MinX && p.X <= env.MaxX && p.Y >= env.MinY && p.Y <= env.MaxY {
+			found[p] = true
+		}
+	}
+	return found
+}
+
+func checkSearch(t *testing.T, tree *gopostgis.RTree[gopostgis.PointS], points []gopostgis.PointS) {
+	t.Helper()
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		x, y := r.Float64()*360-180, r.Float64()*180-90
+		env := gopostgis.Envelope{MinX: x, MinY: y, MaxX: x + r.Float64()*60, MaxY: y + r.Float64()*30}
+		expected := bruteSearch(points, env)
+		found := tree.Search(env)
+		if len(found) != len(expected) {
+			t.Fatal("expected:", len(expected), "found:", len(found))
+		}
+		for _, p := range found {
+			if !expected[p] {
+				t.Fatal("unexpected value:", p)
+			}
+		}
+	}
+}
+
+func TestRTree(t *testing.T) {
+	points := randomPoints(2000, 1)
+
+	t.Run("bulk load", func(t *testing.T) {
+		tree, e := gopostgis.NewRTree(points...)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if tree.Len() != len(points) {
+			t.Error("expected:", len(points), "found:", tree.Len())
+		}
+		checkSearch(t, tree, points)
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		tree, _ := gopostgis.NewRTree[gopostgis.PointS]()
+		for _, p := range points {
+			if e := tree.Insert(p); e != nil {
+				t.Fatal(e)
+			}
+		}
+		if tree.Len() != len(points) {
+			t.Error("expected:", len(points), "found:", tree.Len())
+		}
+		checkSearch(t, tree, points)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		tree, _ := gopostgis.NewRTree(points...)
+		for _, p := range points[:1500] {
+			if !tree.Delete(p) {
+				t.Fatal("delete failed:", p)
+			}
+		}
+		if tree.Delete(points[0]) {
+			t.Error("deleted value should not be found")
+		}
+		if tree.Len() != 500 {
+			t.Error("expected: 500 found:", tree.Len())
+		}
+		checkSearch(t, tree, points[1500:])
+
+		for _, p := range points[1500:] {
+			tree.Delete(p)
+		}
+		if tree.Len() != 0 || len(tree.Search(gopostgis.Envelope{MinX: -180, MinY: -90, MaxX: 180, MaxY: 90})) != 0 {
+			t.Error("tree should be empty")
+		}
+		if e := tree.Insert(points[0]); e != nil || tree.Len() != 1 {
+			t.Error("insert after emptying failed:", e)
+		}
+	})
+
+	t.Run("range and intersects", func(t *testing.T) {
+		lines := [][]gopostgis.Point{
+			{{X: 0, Y: 0, Valid: true}, {X: 10, Y: 10, Valid: true}},
+			{{X: 20, Y: 20, Valid: true}, {X: 30, Y: 20, Valid: true}},
+			{{X: 2, Y: 2, Valid: true}, {X: 3, Y: 3, Valid: true}},
+		}
+		tree, e := gopostgis.NewRTree(lines...)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		found := tree.Range(gopostgis.Envelope{MinX: 1, MinY: 1, MaxX: 5, MaxY: 5})
+		if len(found) != 1 || found[0][0].X != 2 {
+			t.Error("unexpected range result:", found)
+		}
+
+		found, e = tree.Intersects(gopostgis.Point{X: 5, Y: 5, Valid: true})
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(found) != 1 || found[0][0].X != 0 {
+			t.Error("unexpected intersects result:", found)
+		}
+
+		if _, e := tree.Intersects(gopostgis.Point{}); e == nil {
+			t.Error("NULL geometry should fail")
+		}
+	})
+
+	t.Run("nearest", func(t *testing.T) {
+		tree, _ := gopostgis.NewRTree(points...)
+		x, y := 12.5, 41.9
+
+		expected := append([]gopostgis.PointS{}, points...)
+		sort.Slice(expected, func(i, j int) bool {
+			return math.Hypot(expected[i].X-x, expected[i].Y-y) < math.Hypot(expected[j].X-x, expected[j].Y-y)
+		})
+
+		found := tree.Nearest(x, y, 10)
+		if len(found) != 10 {
+			t.Fatal("expected: 10 found:", len(found))
+		}
+		for i := range found {
+			if found[i] != expected[i] {
+				t.Error("expected:", expected[i], "found:", found[i])
+			}
+		}
+
+		if found := tree.Nearest(x, y, 0); len(found) != 0 {
+			t.Error("expected no values found:", found)
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		if _, e := gopostgis.NewRTree(gopostgis.Point{}); e == nil {
+			t.Error("NULL point should fail")
+		}
+		tree, _ := gopostgis.NewRTree[gopostgis.Point]()
+		if e := tree.Insert(gopostgis.Point{Empty: true, Valid: true}); e == nil {
+			t.Error("EMPTY point should fail")
+		}
+		if tree.Delete(gopostgis.Point{X: 1, Valid: true}) {
+			t.Error("delete from empty tree should fail")
+		}
+	})
+}
+
+func BenchmarkRTreeNearest(b *testing.B) {
+	tree, _ := gopostgis.NewRTree(randomPoints(100000, 1)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Nearest(12.5, 41.9, 10)
+	}
+}
@@ -0,0 +1,149 @@
+package shapefile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dBase attribute table of a shapefile.
+// reference - https://www.clicketyclick.dk/databases/xbase/format/dbf.html
+
+// Field of the attribute table.
+type Field struct {
+	Name     string
+	Type     byte
+	Length   int
+	Decimals int
+}
+
+const (
+	dbfHeaderSize = 32
+	dbfFieldSize  = 32
+	dbfDeleted    = '*'
+)
+
+// Reads the attribute table into the records of f.
+//
+// Attribute values are
+//   - string for C fields
+//   - int64 for N fields without decimals, float64 for other N and F fields
+//   - bool for L fields
+//   - time.Time for D fields
+//   - nil for blank values
+//
+// and the trimmed text for other field types.
+func readDBF(r io.Reader, f *File) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if len(data) < dbfHeaderSize {
+		return fmt.Errorf("dbf header too short. got: %d bytes", len(data))
+	}
+
+	numRecords := int(binary.LittleEndian.Uint32(data[4:]))
+	headerSize := int(binary.LittleEndian.Uint16(data[8:]))
+	recordSize := int(binary.LittleEndian.Uint16(data[10:]))
+
+	if headerSize > len(data) || headerSize < dbfHeaderSize+1 {
+		return fmt.Errorf("invalid dbf header size. got: %d", headerSize)
+	}
+
+	size := 1
+	for pos := dbfHeaderSize; pos+dbfFieldSize <= headerSize && data[pos] != '\r'; pos += dbfFieldSize {
+		d := data[pos : pos+dbfFieldSize]
+		field := Field{
+			Name:     string(bytes.TrimRight(d[:11], "\x00 ")),
+			Type:     d[11],
+			Length:   int(d[16]),
+			Decimals: int(d[17]),
+		}
+		f.Fields = append(f.Fields, field)
+		size += field.Length
+	}
+
+	if size != recordSize {
+		return fmt.Errorf("dbf record size mismatch. got: %d expected: %d", recordSize, size)
+	}
+
+	if numRecords != len(f.Records) {
+		return fmt.Errorf("dbf has %d records, shp has %d", numRecords, len(f.Records))
+	}
+
+	if headerSize+numRecords*recordSize > len(data) {
+		return fmt.Errorf("dbf records: %w", io.ErrUnexpectedEOF)
+	}
+
+	for i := range f.Records {
+		rec := data[headerSize+i*recordSize : headerSize+(i+1)*recordSize]
+		f.Records[i].Deleted = rec[0] == dbfDeleted
+		f.Records[i].Attributes = make(map[string]any, len(f.Fields))
+
+		pos := 1
+		for _, field := range f.Fields {
+			v, err := field.parse(rec[pos : pos+field.Length])
+			if err != nil {
+				return fmt.Errorf("dbf record %d field %s: %w", i+1, field.Name, err)
+			}
+			f.Records[i].Attributes[field.Name] = v
+			pos += field.Length
+		}
+	}
+
+	return nil
+}
+
+func (f Field) parse(b []byte) (any, error) {
+	if f.Type == 'C' {
+		return string(bytes.TrimRight(b, "\x00 ")), nil
+	}
+
+	s := strings.Trim(string(b), "\x00 ")
+	if s == "" {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case 'N', 'F':
+		// overflowed numbers are filled with asterisks
+		if strings.Trim(s, "*") == "" {
+			return nil, nil
+		}
+		if f.Type == 'N' && f.Decimals == 0 {
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return v, nil
+			}
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number. got: %q", s)
+		}
+		return v, nil
+
+	case 'L':
+		switch s {
+		case "T", "t", "Y", "y":
+			return true, nil
+		case "F", "f", "N", "n":
+			return false, nil
+		case "?":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid logical. got: %q", s)
+
+	case 'D':
+		v, err := time.Parse("20060102", s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date. got: %q", s)
+		}
+		return v, nil
+	}
+
+	return s, nil
+}
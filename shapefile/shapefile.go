@@ -0,0 +1,425 @@
+// Package shapefile reads ESRI shapefiles into go-postgis types.
+// reference - https://www.esri.com/content/dam/esrisites/sitecore-archive/Files/Pdfs/library/whitepapers/pdfs/shapefile.pdf
+//
+// Shapefiles carry no SRID, their projection lives in a separate .prj
+// file, so geometries are read as the non SRID point types. Point shapes
+// become a single point, MultiPoint shapes a slice of points and PolyLine
+// and Polygon shapes a slice of parts, each part a slice of points.
+//
+// The point type follows the shape type, Point shapes become
+// [gopostgis.Point], M shapes [gopostgis.PointM] and Z shapes
+// [gopostgis.PointZ], or [gopostgis.PointZM] when the record carries the
+// optional measures. Measures below -1e38, the shapefile no data value, and
+// the measures of M shapes missing them are read as NaN.
+package shapefile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// ShapeType of a shapefile or a record.
+type ShapeType int32
+
+const (
+	ShapeNull        ShapeType = 0
+	ShapePoint       ShapeType = 1
+	ShapePolyLine    ShapeType = 3
+	ShapePolygon     ShapeType = 5
+	ShapeMultiPoint  ShapeType = 8
+	ShapePointZ      ShapeType = 11
+	ShapePolyLineZ   ShapeType = 13
+	ShapePolygonZ    ShapeType = 15
+	ShapeMultiPointZ ShapeType = 18
+	ShapePointM      ShapeType = 21
+	ShapePolyLineM   ShapeType = 23
+	ShapePolygonM    ShapeType = 25
+	ShapeMultiPointM ShapeType = 28
+	ShapeMultiPatch  ShapeType = 31
+)
+
+const (
+	shpFileCode   = 9994
+	shpVersion    = 1000
+	shpHeaderSize = 100
+)
+
+// Header of the .shp file.
+type Header struct {
+	ShapeType ShapeType
+	MinX      float64
+	MinY      float64
+	MaxX      float64
+	MaxY      float64
+	MinZ      float64
+	MaxZ      float64
+	MinM      float64
+	MaxM      float64
+}
+
+// Record is a shape along with its attributes.
+//
+// Geometry is nil for null shapes, otherwise one of
+//   - T for Point shapes
+//   - []T for MultiPoint shapes
+//   - [][]T for PolyLine and Polygon shapes, with the parts as stored
+//
+// where T is the point type of the record.
+type Record struct {
+	Number     int
+	ShapeType  ShapeType
+	Geometry   any
+	Deleted    bool
+	Attributes map[string]any
+}
+
+// File is the content of a shapefile.
+type File struct {
+	Header  Header
+	Fields  []Field
+	Records []Record
+}
+
+// Open reads the shapefile name, with or without the .shp extension.
+// The .shx and .dbf files are read when they exist.
+func Open(name string) (*File, error) {
+	name = strings.TrimSuffix(name, ".shp")
+
+	shp, err := os.Open(name + ".shp")
+	if err != nil {
+		return nil, err
+	}
+	defer shp.Close()
+
+	var shx, dbf io.Reader
+
+	if f, err := os.Open(name + ".shx"); err == nil {
+		defer f.Close()
+		shx = f
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if f, err := os.Open(name + ".dbf"); err == nil {
+		defer f.Close()
+		dbf = f
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return Read(shp, shx, dbf)
+}
+
+// Read reads a shapefile from its .shp, .shx and .dbf parts. shx and dbf
+// may be nil. Records are located through the index when shx is given.
+func Read(shp, shx, dbf io.Reader) (*File, error) {
+	data, err := io.ReadAll(shp)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := readHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var offsets []int
+	if shx != nil {
+		if offsets, err = readIndex(shx); err != nil {
+			return nil, err
+		}
+	} else {
+		for pos := shpHeaderSize; pos+8 <= len(data); {
+			offsets = append(offsets, pos)
+			pos += 8 + 2*int(binary.BigEndian.Uint32(data[pos+4:]))
+		}
+	}
+
+	f := &File{Header: header}
+	for _, pos := range offsets {
+		r, err := readRecord(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		f.Records = append(f.Records, r)
+	}
+
+	if dbf != nil {
+		if err := readDBF(dbf, f); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func readHeader(data []byte) (Header, error) {
+	if len(data) < shpHeaderSize {
+		return Header{}, fmt.Errorf("shapefile header too short. got: %d bytes", len(data))
+	}
+
+	if code := binary.BigEndian.Uint32(data); code != shpFileCode {
+		return Header{}, fmt.Errorf("invalid shapefile file code. got: %d", code)
+	}
+
+	if v := binary.LittleEndian.Uint32(data[28:]); v != shpVersion {
+		return Header{}, fmt.Errorf("invalid shapefile version. got: %d", v)
+	}
+
+	b := data[36:]
+	double := func(i int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+
+	return Header{
+		ShapeType: ShapeType(binary.LittleEndian.Uint32(data[32:])),
+		MinX:      double(0),
+		MinY:      double(1),
+		MaxX:      double(2),
+		MaxY:      double(3),
+		MinZ:      double(4),
+		MaxZ:      double(5),
+		MinM:      double(6),
+		MaxM:      double(7),
+	}, nil
+}
+
+// Returns the byte offsets of the records in the .shp file.
+func readIndex(shx io.Reader) ([]int, error) {
+	data, err := io.ReadAll(shx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := readHeader(data); err != nil {
+		return nil, fmt.Errorf("shx: %w", err)
+	}
+
+	data = data[shpHeaderSize:]
+	if len(data)%8 != 0 {
+		return nil, fmt.Errorf("invalid shx length. got: %d bytes", len(data)+shpHeaderSize)
+	}
+
+	offsets := make([]int, 0, len(data)/8)
+	for i := 0; i < len(data); i += 8 {
+		offsets = append(offsets, 2*int(binary.BigEndian.Uint32(data[i:])))
+	}
+
+	return offsets, nil
+}
+
+func readRecord(data []byte, pos int) (Record, error) {
+	if pos < shpHeaderSize || pos+8 > len(data) {
+		return Record{}, fmt.Errorf("shapefile record offset out of range. got: %d", pos)
+	}
+
+	number := int(binary.BigEndian.Uint32(data[pos:]))
+	end := pos + 8 + 2*int(binary.BigEndian.Uint32(data[pos+4:]))
+	if end > len(data) {
+		return Record{}, fmt.Errorf("shapefile record %d: %w", number, io.ErrUnexpectedEOF)
+	}
+
+	s, err := readShape(data[pos+8 : end])
+	if err != nil {
+		return Record{}, fmt.Errorf("shapefile record %d: %w", number, err)
+	}
+
+	return Record{
+		Number:    number,
+		ShapeType: s.typ,
+		Geometry:  s.geometry(),
+	}, nil
+}
+
+// Little endian reader over a record, sticks to the first error.
+type shpCursor struct {
+	b   []byte
+	err error
+}
+
+func (c *shpCursor) int32() int {
+	if c.err != nil || len(c.b) < 4 {
+		c.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	v := int32(binary.LittleEndian.Uint32(c.b))
+	c.b = c.b[4:]
+	return int(v)
+}
+
+func (c *shpCursor) doubles(n int) []float64 {
+	if c.err != nil || n < 0 || len(c.b) < 8*n {
+		c.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = math.Float64frombits(binary.LittleEndian.Uint64(c.b[8*i:]))
+	}
+	c.b = c.b[8*n:]
+	return v
+}
+
+// Values below this are no data measures.
+const noDataM = -1e38
+
+// Reads n measures, no data or missing measures become NaN.
+func (c *shpCursor) measures(n int, present bool) []float64 {
+	m := make([]float64, n)
+	if present {
+		copy(m, c.doubles(n))
+	}
+
+	for i, v := range m {
+		if !present || v < noDataM {
+			m[i] = math.NaN()
+		}
+	}
+
+	return m
+}
+
+type shape struct {
+	typ   ShapeType
+	parts []int
+	xy    []float64
+	z     []float64
+	m     []float64
+}
+
+func readShape(b []byte) (*shape, error) {
+	c := &shpCursor{b: b}
+	s := &shape{typ: ShapeType(c.int32())}
+
+	switch s.typ {
+	case ShapeNull:
+		return s, c.err
+
+	case ShapePoint, ShapePointZ, ShapePointM:
+		s.xy = c.doubles(2)
+		switch {
+		case s.typ == ShapePointZ:
+			s.z = c.doubles(1)
+			if len(c.b) >= 8 {
+				s.m = c.measures(1, true)
+			}
+		case s.typ == ShapePointM:
+			s.m = c.measures(1, len(c.b) >= 8)
+		}
+		return s, c.err
+
+	case ShapeMultiPoint, ShapeMultiPointZ, ShapeMultiPointM,
+		ShapePolyLine, ShapePolyLineZ, ShapePolyLineM,
+		ShapePolygon, ShapePolygonZ, ShapePolygonM:
+
+		c.doubles(4) // bounding box
+
+		numParts := 0
+		if !s.multiPoint() {
+			numParts = c.int32()
+		}
+		numPoints := c.int32()
+		if c.err == nil && (numParts < 0 || numPoints < 0 || 4*numParts+16*numPoints > len(c.b)) {
+			return nil, fmt.Errorf("invalid shape size. got: %d parts %d points", numParts, numPoints)
+		}
+
+		for i := 0; i < numParts; i++ {
+			start := c.int32()
+			if c.err == nil && (start < 0 || start > numPoints || (i > 0 && start < s.parts[i-1])) {
+				return nil, fmt.Errorf("invalid shape part index. got: %d", start)
+			}
+			s.parts = append(s.parts, start)
+		}
+		s.xy = c.doubles(2 * numPoints)
+
+		if s.hasZ() {
+			c.doubles(2) // z range
+			s.z = c.doubles(numPoints)
+		}
+
+		// measures are optional in Z and M shapes
+		present := len(c.b) >= 16+8*numPoints
+		if present {
+			c.doubles(2) // m range
+		}
+		if s.hasM() || (s.hasZ() && present) {
+			s.m = c.measures(numPoints, present)
+		}
+		return s, c.err
+	}
+
+	return nil, fmt.Errorf("shape type not supported. got: %d", s.typ)
+}
+
+func (s *shape) multiPoint() bool {
+	return s.typ == ShapeMultiPoint || s.typ == ShapeMultiPointZ || s.typ == ShapeMultiPointM
+}
+
+func (s *shape) hasZ() bool {
+	return s.typ == ShapePointZ || s.typ == ShapeMultiPointZ || s.typ == ShapePolyLineZ || s.typ == ShapePolygonZ
+}
+
+func (s *shape) hasM() bool {
+	return s.typ == ShapePointM || s.typ == ShapeMultiPointM || s.typ == ShapePolyLineM || s.typ == ShapePolygonM
+}
+
+func (s *shape) geometry() any {
+	if s.typ == ShapeNull {
+		return nil
+	}
+
+	switch {
+	case s.z != nil && s.m != nil:
+		return convert(s, func(i int) gopostgis.PointZM {
+			return gopostgis.PointZM{X: s.xy[2*i], Y: s.xy[2*i+1], Z: s.z[i], M: s.m[i], Valid: true}
+		})
+	case s.z != nil:
+		return convert(s, func(i int) gopostgis.PointZ {
+			return gopostgis.PointZ{X: s.xy[2*i], Y: s.xy[2*i+1], Z: s.z[i], Valid: true}
+		})
+	case s.m != nil:
+		return convert(s, func(i int) gopostgis.PointM {
+			return gopostgis.PointM{X: s.xy[2*i], Y: s.xy[2*i+1], M: s.m[i], Valid: true}
+		})
+	default:
+		return convert(s, func(i int) gopostgis.Point {
+			return gopostgis.Point{X: s.xy[2*i], Y: s.xy[2*i+1], Valid: true}
+		})
+	}
+}
+
+func convert[T any](s *shape, point func(i int) T) any {
+	n := len(s.xy) / 2
+
+	switch {
+	case s.typ == ShapePoint || s.typ == ShapePointZ || s.typ == ShapePointM:
+		return point(0)
+
+	case s.multiPoint():
+		points := make([]T, n)
+		for i := range points {
+			points[i] = point(i)
+		}
+		return points
+	}
+
+	parts := make([][]T, len(s.parts))
+	for p := range parts {
+		end := n
+		if p+1 < len(s.parts) {
+			end = s.parts[p+1]
+		}
+		for i := s.parts[p]; i < end; i++ {
+			parts[p] = append(parts[p], point(i))
+		}
+	}
+
+	return parts
+}
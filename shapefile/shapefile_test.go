@@ -0,0 +1,269 @@
+package shapefile_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+	"github.com/asif-mahmud/go-postgis/shapefile"
+)
+
+func TestPoints(t *testing.T) {
+	f, e := shapefile.Open("testdata/points.shp")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	t.Run("header", func(t *testing.T) {
+		expected := shapefile.Header{ShapeType: shapefile.ShapePoint, MinX: -0.1276, MinY: 23.8103, MaxX: 90.4125, MaxY: 51.5072}
+		if f.Header != expected {
+			t.Error("expected:", expected, "found:", f.Header)
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		expected := []shapefile.Field{
+			{Name: "NAME", Type: 'C', Length: 12},
+			{Name: "POP", Type: 'N', Length: 9},
+			{Name: "AREA", Type: 'N', Length: 8, Decimals: 2},
+			{Name: "CAPITAL", Type: 'L', Length: 1},
+			{Name: "FOUNDED", Type: 'D', Length: 8},
+		}
+		if !reflect.DeepEqual(f.Fields, expected) {
+			t.Error("expected:", expected, "found:", f.Fields)
+		}
+	})
+
+	t.Run("records", func(t *testing.T) {
+		expected := []shapefile.Record{
+			{
+				Number:    1,
+				ShapeType: shapefile.ShapePoint,
+				Geometry:  gopostgis.Point{X: 90.4125, Y: 23.8103, Valid: true},
+				Attributes: map[string]any{
+					"NAME":    "Dhaka",
+					"POP":     int64(10278882),
+					"AREA":    306.38,
+					"CAPITAL": true,
+					"FOUNDED": time.Date(1608, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				Number:    2,
+				ShapeType: shapefile.ShapeNull,
+				Deleted:   true,
+				Attributes: map[string]any{
+					"NAME":    "Nowhere",
+					"POP":     nil,
+					"AREA":    nil,
+					"CAPITAL": nil,
+					"FOUNDED": nil,
+				},
+			},
+			{
+				Number:    3,
+				ShapeType: shapefile.ShapePoint,
+				Geometry:  gopostgis.Point{X: -0.1276, Y: 51.5072, Valid: true},
+				Attributes: map[string]any{
+					"NAME":    "London",
+					"POP":     int64(8982000),
+					"AREA":    1572.0,
+					"CAPITAL": false,
+					"FOUNDED": time.Date(47, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		}
+		if !reflect.DeepEqual(f.Records, expected) {
+			t.Error("expected:", expected, "found:", f.Records)
+		}
+	})
+}
+
+func TestShapes(t *testing.T) {
+	t.Run("polyline z with measures", func(t *testing.T) {
+		f, e := shapefile.Open("testdata/roads")
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := [][]gopostgis.PointZM{
+			{{X: 0, Y: 0, Z: 1, M: 0, Valid: true}, {X: 10, Y: 0, Z: 2, M: 10, Valid: true}},
+			{{X: 10, Y: 5, Z: 3, M: 15, Valid: true}, {X: 20, Y: 5, Z: 4, M: 25, Valid: true}},
+		}
+		if len(f.Records) != 2 || !reflect.DeepEqual(f.Records[0].Geometry, expected) {
+			t.Error("expected:", expected, "found:", f.Records)
+		}
+		if f.Records[1].Attributes["ROAD"] != "Side" {
+			t.Error("expected: Side found:", f.Records[1].Attributes["ROAD"])
+		}
+	})
+
+	t.Run("polygon m", func(t *testing.T) {
+		f, e := shapefile.Open("testdata/parcels")
+		if e != nil {
+			t.Fatal(e)
+		}
+		rings, ok := f.Records[0].Geometry.([][]gopostgis.PointM)
+		if !ok || len(rings) != 2 || len(rings[0]) != 5 || len(rings[1]) != 5 {
+			t.Fatal("unexpected geometry:", f.Records[0].Geometry)
+		}
+		expected := gopostgis.PointM{X: 4, Y: 2, M: 6, Valid: true}
+		if rings[1][1] != expected {
+			t.Error("expected:", expected, "found:", rings[1][1])
+		}
+		if f.Records[0].Attributes["ID"] != int64(7) {
+			t.Error("expected: 7 found:", f.Records[0].Attributes["ID"])
+		}
+	})
+
+	t.Run("multipoint z without measures", func(t *testing.T) {
+		f, e := shapefile.Open("testdata/wells")
+		if e != nil {
+			t.Fatal(e)
+		}
+		expected := []gopostgis.PointZ{
+			{X: 1, Y: 2, Z: 3, Valid: true},
+			{X: 4, Y: 5, Z: 6, Valid: true},
+		}
+		if !reflect.DeepEqual(f.Records[0].Geometry, expected) {
+			t.Error("expected:", expected, "found:", f.Records[0].Geometry)
+		}
+		if f.Records[0].Attributes["DEPTH"] != 12.5 {
+			t.Error("expected: 12.5 found:", f.Records[0].Attributes["DEPTH"])
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	shp, e := os.ReadFile("testdata/points.shp")
+	if e != nil {
+		t.Fatal(e)
+	}
+	dbf, e := os.ReadFile("testdata/points.dbf")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	t.Run("without index", func(t *testing.T) {
+		f, e := shapefile.Read(bytes.NewReader(shp), nil, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(f.Records) != 3 || f.Records[2].Number != 3 || f.Records[2].Attributes != nil {
+			t.Error("unexpected records:", f.Records)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, e := shapefile.Read(bytes.NewReader(shp[:50]), nil, nil); e == nil {
+			t.Error("short header should fail")
+		}
+		if _, e := shapefile.Read(bytes.NewReader(shp[:len(shp)-4]), nil, nil); e == nil {
+			t.Error("truncated record should fail")
+		}
+		if _, e := shapefile.Read(bytes.NewReader(shp), nil, bytes.NewReader(dbf[:40])); e == nil {
+			t.Error("truncated dbf should fail")
+		}
+
+		bad := append([]byte{}, shp...)
+		bad[3] = 0
+		if _, e := shapefile.Read(bytes.NewReader(bad), nil, nil); e == nil {
+			t.Error("invalid file code should fail")
+		}
+
+		if _, e := shapefile.Open("testdata/missing"); e == nil {
+			t.Error("missing file should fail")
+		}
+	})
+}
+
+// Little endian record content from int32 and float64 values.
+func content(values ...any) []byte {
+	var b bytes.Buffer
+	for _, v := range values {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	return b.Bytes()
+}
+
+// Builds a .shp of one shape type from record contents.
+func shpFile(typ shapefile.ShapeType, records ...[]byte) []byte {
+	var b bytes.Buffer
+	b.Write(make([]byte, 100))
+	for i, r := range records {
+		binary.Write(&b, binary.BigEndian, [2]int32{int32(i + 1), int32(len(r)/2 + 2)})
+		binary.Write(&b, binary.LittleEndian, int32(typ))
+		b.Write(r)
+	}
+
+	data := b.Bytes()
+	binary.BigEndian.PutUint32(data, 9994)
+	binary.BigEndian.PutUint32(data[24:], uint32(len(data)/2))
+	binary.LittleEndian.PutUint32(data[28:], 1000)
+	binary.LittleEndian.PutUint32(data[32:], uint32(typ))
+	return data
+}
+
+func TestMeasures(t *testing.T) {
+	t.Run("point m no data", func(t *testing.T) {
+		shp := shpFile(shapefile.ShapePointM, content(1.0, 2.0, -1e39))
+		f, e := shapefile.Read(bytes.NewReader(shp), nil, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		p, ok := f.Records[0].Geometry.(gopostgis.PointM)
+		if !ok || p.X != 1 || p.Y != 2 || !math.IsNaN(p.M) {
+			t.Error("expected: PointM with NaN M found:", f.Records[0].Geometry)
+		}
+	})
+
+	t.Run("point m without measure", func(t *testing.T) {
+		shp := shpFile(shapefile.ShapePointM, content(1.0, 2.0))
+		f, e := shapefile.Read(bytes.NewReader(shp), nil, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		p, ok := f.Records[0].Geometry.(gopostgis.PointM)
+		if !ok || !math.IsNaN(p.M) {
+			t.Error("expected: PointM with NaN M found:", f.Records[0].Geometry)
+		}
+	})
+
+	// bbox, part and point counts, part index and two points
+	line := content(0.0, 0.0, 10.0, 0.0, int32(1), int32(2), int32(0), 0.0, 0.0, 10.0, 0.0)
+
+	t.Run("polyline m without measures", func(t *testing.T) {
+		f, e := shapefile.Read(bytes.NewReader(shpFile(shapefile.ShapePolyLineM, line)), nil, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		parts, ok := f.Records[0].Geometry.([][]gopostgis.PointM)
+		if !ok || len(parts) != 1 || len(parts[0]) != 2 {
+			t.Fatal("unexpected geometry:", f.Records[0].Geometry)
+		}
+		for _, p := range parts[0] {
+			if !math.IsNaN(p.M) {
+				t.Error("expected: NaN found:", p.M)
+			}
+		}
+	})
+
+	t.Run("polyline m no data", func(t *testing.T) {
+		withM := append(append([]byte{}, line...), content(-1e39, 5.0, -1e39, 5.0)...)
+		f, e := shapefile.Read(bytes.NewReader(shpFile(shapefile.ShapePolyLineM, withM)), nil, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		parts, ok := f.Records[0].Geometry.([][]gopostgis.PointM)
+		if !ok || len(parts) != 1 || len(parts[0]) != 2 {
+			t.Fatal("unexpected geometry:", f.Records[0].Geometry)
+		}
+		if !math.IsNaN(parts[0][0].M) || parts[0][1].M != 5 {
+			t.Error("expected: [NaN 5] found:", parts[0][0].M, parts[0][1].M)
+		}
+	})
+}
@@ -0,0 +1,485 @@
+package gopostgis
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Line simplification, same as postgis ST_Simplify, ST_SimplifyVW and
+// ST_SimplifyPreserveTopology. Simplified geometries keep the original
+// points, so Z, M and SRID are preserved.
+
+// Simplify returns line simplified with the Douglas-Peucker algorithm.
+// Points closer than tolerance to the simplified line are removed, the
+// end points are always kept.
+func Simplify[T PointType](line []T, tolerance float64) []T {
+	xy := pointsXY(line)
+	keep := make([]bool, len(line))
+	douglasPeucker(xy, keep, tolerance)
+
+	return keptPoints(line, keep)
+}
+
+// SimplifyPolygon simplifies every ring of polygon with [Simplify]. Rings
+// collapsing to less than 4 points are removed, nil is returned if the
+// exterior ring collapses.
+func SimplifyPolygon[T PointType](polygon [][]T, tolerance float64) [][]T {
+	var simplified [][]T
+	for i, ring := range polygon {
+		r := Simplify(ring, tolerance)
+		if len(r) < 4 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+		simplified = append(simplified, r)
+	}
+
+	return simplified
+}
+
+// X, Y coordinates of points.
+func pointsXY[T PointType](points []T) [][2]float64 {
+	xy := make([][2]float64, len(points))
+	for i, p := range points {
+		d := p.data()
+		xy[i] = [2]float64{d.coords[0], d.coords[1]}
+	}
+
+	return xy
+}
+
+func keptPoints[T PointType](points []T, keep []bool) []T {
+	var kept []T
+	for i, p := range points {
+		if keep[i] {
+			kept = append(kept, p)
+		}
+	}
+
+	return kept
+}
+
+// Marks the points of xy kept by Douglas-Peucker.
+func douglasPeucker(xy [][2]float64, keep []bool, tolerance float64) {
+	if len(xy) == 0 {
+		return
+	}
+
+	keep[0], keep[len(xy)-1] = true, true
+
+	stack := [][2]int{{0, len(xy) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		i, d := farthestPoint(xy, s[0], s[1])
+		if i >= 0 && d > tolerance {
+			keep[i] = true
+			stack = append(stack, [2]int{s[0], i}, [2]int{i, s[1]})
+		}
+	}
+}
+
+// Point of xy between from and to farthest from the segment joining
+// them, -1 if there is none.
+func farthestPoint(xy [][2]float64, from, to int) (int, float64) {
+	index, max := -1, -1.0
+	for i := from + 1; i < to; i++ {
+		if d := segmentDistance(xy[i], xy[from], xy[to]); d > max {
+			index, max = i, d
+		}
+	}
+
+	return index, max
+}
+
+// Distance of p from the segment a, b.
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := segmentParam(p, a, b)
+
+	return math.Hypot(p[0]-a[0]-t*dx, p[1]-a[1]-t*dy)
+}
+
+// Parameter in [0, 1] of the point of segment a, b closest to p.
+func segmentParam(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l := dx*dx + dy*dy
+	if l == 0 {
+		return 0
+	}
+
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / l
+	return math.Max(0, math.Min(1, t))
+}
+
+// SimplifyVW returns line simplified with the Visvalingam-Whyatt
+// algorithm. Points forming a triangle smaller than area with their
+// neighbors are removed, smallest first. The end points are always
+// kept, and closed lines keep at least 4 points.
+func SimplifyVW[T PointType](line []T, area float64) []T {
+	n := len(line)
+	if n < 3 {
+		return append([]T(nil), line...)
+	}
+
+	xy := pointsXY(line)
+	closed := xy[0] == xy[n-1]
+
+	prev := make([]int, n)
+	next := make([]int, n)
+	for i := range line {
+		prev[i], next[i] = i-1, i+1
+	}
+
+	triangle := func(i int) float64 {
+		a, b, c := xy[prev[i]], xy[i], xy[next[i]]
+		return math.Abs((b[0]-a[0])*(c[1]-a[1])-(c[0]-a[0])*(b[1]-a[1])) / 2
+	}
+
+	q := &vwQueue{}
+	areas := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		areas[i] = triangle(i)
+		heap.Push(q, vwItem{index: i, area: areas[i]})
+	}
+
+	keep := make([]bool, n)
+	for i := range keep {
+		keep[i] = true
+	}
+
+	remaining := n
+	for q.Len() > 0 {
+		item := heap.Pop(q).(vwItem)
+		i := item.index
+		if !keep[i] || item.area != areas[i] {
+			continue // stale
+		}
+		if item.area >= area || (closed && remaining <= 4) {
+			break
+		}
+
+		keep[i] = false
+		remaining--
+
+		p, nx := prev[i], next[i]
+		next[p], prev[nx] = nx, p
+
+		// neighbors can not become smaller than the removed point,
+		// otherwise they would be removed before it
+		for _, j := range [2]int{p, nx} {
+			if j > 0 && j < n-1 {
+				areas[j] = math.Max(triangle(j), item.area)
+				heap.Push(q, vwItem{index: j, area: areas[j]})
+			}
+		}
+	}
+
+	return keptPoints(line, keep)
+}
+
+type vwItem struct {
+	index int
+	area  float64
+}
+
+type vwQueue []vwItem
+
+func (q vwQueue) Len() int            { return len(q) }
+func (q vwQueue) Less(i, j int) bool  { return q[i].area < q[j].area }
+func (q vwQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *vwQueue) Push(x interface{}) { *q = append(*q, x.(vwItem)) }
+func (q *vwQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// SimplifyPreserveTopology simplifies lines with Douglas-Peucker without
+// changing their topology. Simplified lines do not intersect each other or
+// themselves where the input does not, closed lines (rings) keep at least
+// 4 points and stay on the same side of every other ring. lines can be the
+// parts of a multi line or the rings of one or more polygons, so a valid
+// polygon stays valid.
+func SimplifyPreserveTopology[T PointType](lines [][]T, tolerance float64) [][]T {
+	xy := make([][][2]float64, len(lines))
+	keep := make([][]bool, len(lines))
+	for l, line := range lines {
+		xy[l] = pointsXY(line)
+		keep[l] = make([]bool, len(line))
+		douglasPeucker(xy[l], keep[l], tolerance)
+	}
+
+	inside := ringContainment(xy, nil)
+
+	// put back the farthest dropped point of every conflicting section
+	// until there is no conflict, the input is the worst case
+	for {
+		sections := topologySections(xy, keep)
+		conflicts := topologyConflicts(xy, sections)
+		conflicts = append(conflicts, containmentConflicts(xy, keep, sections, inside)...)
+		if len(conflicts) == 0 {
+			break
+		}
+
+		for _, c := range conflicts {
+			s := sections[c]
+			i, _ := farthestPoint(xy[s.line], s.from, s.to)
+			keep[s.line][i] = true
+		}
+	}
+
+	simplified := make([][]T, len(lines))
+	for l, line := range lines {
+		simplified[l] = keptPoints(line, keep[l])
+	}
+
+	return simplified
+}
+
+// SimplifyMultiPolygon simplifies the rings of every polygon of
+// multiPolygon together with [SimplifyPreserveTopology], so rings stay
+// valid and polygons do not overlap where the input does not.
+func SimplifyMultiPolygon[T PointType](multiPolygon [][][]T, tolerance float64) [][][]T {
+	var rings [][]T
+	for _, polygon := range multiPolygon {
+		rings = append(rings, polygon...)
+	}
+
+	rings = SimplifyPreserveTopology(rings, tolerance)
+
+	simplified := make([][][]T, len(multiPolygon))
+	for i, polygon := range multiPolygon {
+		simplified[i], rings = rings[:len(polygon)], rings[len(polygon):]
+	}
+
+	return simplified
+}
+
+// Segment of a simplified line, from and to index the original points.
+type topologySection struct {
+	line      int
+	from, to  int
+	closed    bool
+	collapsed bool
+	first     bool
+	last      bool
+	env       Envelope
+	shortcut  bool
+}
+
+func topologySections(xy [][][2]float64, keep [][]bool) []topologySection {
+	var sections []topologySection
+	for l, points := range xy {
+		n := len(points)
+		if n < 2 {
+			continue
+		}
+
+		closed := isClosed(points)
+		kept := 0
+		for _, k := range keep[l] {
+			if k {
+				kept++
+			}
+		}
+
+		start := len(sections)
+		prev := 0
+		for i := 1; i < n; i++ {
+			if !keep[l][i] {
+				continue
+			}
+			a, b := points[prev], points[i]
+			sections = append(sections, topologySection{
+				line:     l,
+				from:     prev,
+				to:       i,
+				closed:   closed,
+				env:      Envelope{MinX: math.Min(a[0], b[0]), MinY: math.Min(a[1], b[1]), MaxX: math.Max(a[0], b[0]), MaxY: math.Max(a[1], b[1])},
+				shortcut: i-prev > 1,
+			})
+			prev = i
+		}
+		sections[start].first = true
+		sections[len(sections)-1].last = true
+
+		// a ring collapsed below 4 points conflicts with itself
+		if closed && kept < 4 && n >= 4 {
+			for s := start; s < len(sections); s++ {
+				sections[s].collapsed = true
+			}
+		}
+	}
+
+	return sections
+}
+
+// Indexes of the shortcut sections intersecting another section.
+func topologyConflicts(xy [][][2]float64, sections []topologySection) []int {
+	conflict := make([]bool, len(sections))
+	envs := make([]Envelope, len(sections))
+	for i, s := range sections {
+		envs[i] = s.env
+		if s.collapsed && s.shortcut {
+			conflict[i] = true
+		}
+	}
+
+	intersectingPairs(envs, func(i, j int) {
+		a, b := &sections[i], &sections[j]
+		if (a.shortcut || b.shortcut) && sectionsIntersect(xy, a, b) {
+			conflict[i] = conflict[i] || a.shortcut
+			conflict[j] = conflict[j] || b.shortcut
+		}
+	})
+
+	var indexes []int
+	for i, c := range conflict {
+		if c {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// For every pair of closed lines i, j reports whether the first point of
+// i is inside j, only using the kept points of j if keep is not nil.
+func ringContainment(xy [][][2]float64, keep [][]bool) [][]bool {
+	inside := make([][]bool, len(xy))
+	for i := range xy {
+		inside[i] = make([]bool, len(xy))
+		if !isClosed(xy[i]) {
+			continue
+		}
+		for j := range xy {
+			if i == j || !isClosed(xy[j]) {
+				continue
+			}
+			var k []bool
+			if keep != nil {
+				k = keep[j]
+			}
+			inside[i][j] = pointInRing(xy[i][0], xy[j], k)
+		}
+	}
+
+	return inside
+}
+
+// Sections of the rings whose simplification changed the side of another
+// ring. Rings do not cross once there are no topology conflicts, so the
+// side of their first point is the side of the whole ring.
+func containmentConflicts(xy [][][2]float64, keep [][]bool, sections []topologySection, inside [][]bool) []int {
+	changed := make([]bool, len(xy))
+	now := ringContainment(xy, keep)
+	for i := range now {
+		for j := range now[i] {
+			if now[i][j] != inside[i][j] {
+				changed[j] = true
+			}
+		}
+	}
+
+	var indexes []int
+	for i, s := range sections {
+		if changed[s.line] && s.shortcut {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+func isClosed(points [][2]float64) bool {
+	return len(points) >= 4 && points[0] == points[len(points)-1]
+}
+
+// Ray casting test of p against ring, skipping the points not kept.
+func pointInRing(p [2]float64, ring [][2]float64, keep []bool) bool {
+	inside := false
+	prev := -1
+	for i := range ring {
+		if keep != nil && !keep[i] {
+			continue
+		}
+		if prev >= 0 {
+			a, b := ring[prev], ring[i]
+			if (a[1] > p[1]) != (b[1] > p[1]) && p[0] < a[0]+(p[1]-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+				inside = !inside
+			}
+		}
+		prev = i
+	}
+
+	return inside
+}
+
+// Reports whether sections a and b intersect anywhere other than at the
+// point shared by consecutive sections.
+func sectionsIntersect(xy [][][2]float64, a, b *topologySection) bool {
+	p1, p2 := xy[a.line][a.from], xy[a.line][a.to]
+	q1, q2 := xy[b.line][b.from], xy[b.line][b.to]
+
+	if a.line == b.line {
+		// consecutive sections only share a point, unless they fold back
+		adjacent := a.to == b.from || b.to == a.from ||
+			(a.closed && ((a.first && b.last) || (a.last && b.first)))
+		if adjacent {
+			return collinearOverlap(p1, p2, q1, q2)
+		}
+	}
+
+	return segmentsIntersect(p1, p2, q1, q2)
+}
+
+func orientation(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+func onSegment(p, a, b [2]float64) bool {
+	return math.Min(a[0], b[0]) <= p[0] && p[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= p[1] && p[1] <= math.Max(a[1], b[1])
+}
+
+func segmentsIntersect(p1, p2, q1, q2 [2]float64) bool {
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(p1, q1, q2)) ||
+		(d2 == 0 && onSegment(p2, q1, q2)) ||
+		(d3 == 0 && onSegment(q1, p1, p2)) ||
+		(d4 == 0 && onSegment(q2, p1, p2))
+}
+
+// Reports whether segments sharing an end point overlap along a line.
+func collinearOverlap(p1, p2, q1, q2 [2]float64) bool {
+	if orientation(p1, p2, q1) != 0 || orientation(p1, p2, q2) != 0 {
+		return false
+	}
+
+	// the shared point lies on both, any other common point is an overlap
+	for _, p := range [2][2]float64{q1, q2} {
+		if p != p1 && p != p2 && onSegment(p, p1, p2) {
+			return true
+		}
+	}
+	for _, p := range [2][2]float64{p1, p2} {
+		if p != q1 && p != q2 && onSegment(p, q1, q2) {
+			return true
+		}
+	}
+
+	return false
+}
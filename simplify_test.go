@@ -0,0 +1,230 @@
+package gopostgis_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func xyLine(coords ...float64) []gopostgis.Point {
+	points := make([]gopostgis.Point, len(coords)/2)
+	for i := range points {
+		points[i] = gopostgis.Point{X: coords[2*i], Y: coords[2*i+1], Valid: true}
+	}
+	return points
+}
+
+func TestSimplify(t *testing.T) {
+	t.Run("douglas peucker", func(t *testing.T) {
+		line := xyLine(0, 0, 1, 0.1, 2, 0, 3, 5, 4, 6, 5, 7.05, 6, 8, 7, 9.1, 8, 10)
+
+		found := gopostgis.Simplify(line, 0.5)
+		expected := xyLine(0, 0, 2, 0, 3, 5, 8, 10)
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+
+		if found := gopostgis.Simplify(line, 0); len(found) != len(line) {
+			t.Error("expected:", line, "found:", found)
+		}
+	})
+
+	t.Run("keeps z, m and srid", func(t *testing.T) {
+		line := []gopostgis.PointZMS{
+			{SRID: 4326, X: 0, Y: 0, Z: 1, M: 10, Valid: true},
+			{SRID: 4326, X: 1, Y: 0.01, Z: 2, M: 11, Valid: true},
+			{SRID: 4326, X: 2, Y: 0, Z: 3, M: 12, Valid: true},
+		}
+		found := gopostgis.Simplify(line, 0.1)
+		expected := []gopostgis.PointZMS{line[0], line[2]}
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+
+	t.Run("polygon", func(t *testing.T) {
+		polygon := [][]gopostgis.Point{
+			xyLine(0, 0, 5, 0.1, 10, 0, 10, 10, 0, 10, 0, 0),
+			xyLine(4, 4, 4.5, 4, 4.5, 4.5, 4, 4),
+		}
+
+		found := gopostgis.SimplifyPolygon(polygon, 1)
+		expected := [][]gopostgis.Point{xyLine(0, 0, 10, 0, 10, 10, 0, 10, 0, 0)}
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+
+		if found := gopostgis.SimplifyPolygon(polygon, 20); found != nil {
+			t.Error("expected: nil found:", found)
+		}
+	})
+
+	t.Run("visvalingam", func(t *testing.T) {
+		line := xyLine(0, 0, 1, 0.1, 2, 0, 3, 3, 4, 0)
+
+		// (1, 0.1) has the area 0.1, (2, 0) and (3, 3) have 3 once it is gone
+		found := gopostgis.SimplifyVW(line, 2)
+		expected := xyLine(0, 0, 2, 0, 3, 3, 4, 0)
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+
+		if found := gopostgis.SimplifyVW(line, 0.05); !reflect.DeepEqual(found, line) {
+			t.Error("expected:", line, "found:", found)
+		}
+
+		found = gopostgis.SimplifyVW(line, 10)
+		expected = xyLine(0, 0, 4, 0)
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+
+		ring := xyLine(0, 0, 1, 0, 1, 1, 0, 1, 0, 0)
+		if found := gopostgis.SimplifyVW(ring, 100); len(found) != 4 {
+			t.Error("expected 4 points found:", found)
+		}
+
+		short := xyLine(0, 0, 1, 1)
+		if found := gopostgis.SimplifyVW(short, 100); !reflect.DeepEqual(found, short) {
+			t.Error("expected:", short, "found:", found)
+		}
+	})
+}
+
+func TestSimplifyPreserveTopology(t *testing.T) {
+	t.Run("hole crossing", func(t *testing.T) {
+		// the spike of the shell holds a hole, dropping it would cross the hole
+		rings := [][]gopostgis.Point{
+			xyLine(0, 0, 10, 0, 10, 10, 5, 11, 0, 10, 0, 0),
+			xyLine(4.9, 9.5, 5.1, 9.5, 5.1, 10.5, 4.9, 10.5, 4.9, 9.5),
+		}
+
+		if found := gopostgis.SimplifyPolygon(rings, 1.5); len(found[0]) != 5 {
+			t.Fatal("plain simplify should drop the spike:", found)
+		}
+
+		found := gopostgis.SimplifyPreserveTopology(rings, 1.5)
+		if !reflect.DeepEqual(found, rings) {
+			t.Error("expected:", rings, "found:", found)
+		}
+	})
+
+	t.Run("hole left outside", func(t *testing.T) {
+		rings := [][]gopostgis.Point{
+			xyLine(0, 0, 10, 0, 10, 10, 5, 11, 0, 10, 0, 0),
+			xyLine(4.9, 10.2, 5.1, 10.2, 5.1, 10.5, 4.9, 10.5, 4.9, 10.2),
+		}
+
+		found := gopostgis.SimplifyPreserveTopology(rings, 1.5)
+		if len(found[0]) != 6 {
+			t.Error("expected the spike to be kept found:", found[0])
+		}
+	})
+
+	t.Run("line crossing", func(t *testing.T) {
+		// the second line ends under the bump of the first one
+		lines := [][]gopostgis.Point{
+			xyLine(0, 0, 5, 1, 10, 0),
+			xyLine(5, 0.5, 5, -1),
+		}
+		found := gopostgis.SimplifyPreserveTopology(lines, 1.5)
+		if !reflect.DeepEqual(found, lines) {
+			t.Error("expected:", lines, "found:", found)
+		}
+	})
+
+	t.Run("ring collapse", func(t *testing.T) {
+		ring := xyLine(0, 0, 1, 0, 1, 1, 0, 1, 0, 0)
+		found := gopostgis.SimplifyPreserveTopology([][]gopostgis.Point{ring}, 10)
+		if len(found[0]) < 4 {
+			t.Error("expected at least 4 points found:", found[0])
+		}
+	})
+
+	t.Run("unconstrained", func(t *testing.T) {
+		lines := [][]gopostgis.Point{
+			xyLine(0, 0, 1, 0.1, 2, 0),
+			xyLine(0, 5, 1, 5.1, 2, 5),
+		}
+		found := gopostgis.SimplifyPreserveTopology(lines, 0.5)
+		expected := [][]gopostgis.Point{xyLine(0, 0, 2, 0), xyLine(0, 5, 2, 5)}
+		if !reflect.DeepEqual(found, expected) {
+			t.Error("expected:", expected, "found:", found)
+		}
+	})
+}
+
+func TestSimplifyMultiPolygon(t *testing.T) {
+	t.Run("neighbor in notch", func(t *testing.T) {
+		// dropping the notch of the first polygon would cover the second one
+		polygons := [][][]gopostgis.Point{
+			{xyLine(0, 0, 10, 0, 10, 10, 5, 9, 0, 10, 0, 0)},
+			{xyLine(4.9, 9.5, 5.1, 9.5, 5.1, 9.8, 4.9, 9.8, 4.9, 9.5)},
+		}
+
+		if found := gopostgis.SimplifyPreserveTopology(polygons[0], 1.5); len(found[0]) != 5 {
+			t.Fatal("a lone polygon should drop the notch:", found)
+		}
+
+		found := gopostgis.SimplifyMultiPolygon(polygons, 1.5)
+		if !reflect.DeepEqual(found, polygons) {
+			t.Error("expected:", polygons, "found:", found)
+		}
+	})
+
+	t.Run("valid rings", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 50; i++ {
+			var polygons [][][]gopostgis.Point
+			for j := 0; j < 3; j++ {
+				// shells and holes interleave but never touch
+				cx, cy := float64(25*j), 0.0
+				polygons = append(polygons, [][]gopostgis.Point{
+					wiggly(r, cx, cy, 6.5, 12, 40),
+					wiggly(r, cx, cy, 3, 6.4, 20),
+				})
+			}
+
+			found := gopostgis.SimplifyMultiPolygon(polygons, 5)
+			if len(found) != len(polygons) {
+				t.Fatal("expected:", len(polygons), "polygons found:", len(found))
+			}
+			for j, polygon := range found {
+				if len(polygon) != 2 || !gopostgis.IsValidPolygon(polygon) {
+					t.Error("invalid polygon", j, "of case", i, polygon)
+				}
+			}
+		}
+	})
+}
+
+// Closed ring of n points with a random radius between min and max.
+func wiggly(r *rand.Rand, cx, cy, min, max float64, n int) []gopostgis.Point {
+	ring := make([]gopostgis.Point, 0, n+1)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		d := min + (max-min)*r.Float64()
+		ring = append(ring, gopostgis.Point{X: cx + d*math.Cos(a), Y: cy + d*math.Sin(a), Valid: true})
+	}
+
+	return append(ring, ring[0])
+}
+
+func BenchmarkSimplifyPreserveTopology(b *testing.B) {
+	// close parallel wiggly lines, like a dense road network
+	lines := make([][]gopostgis.Point, 500)
+	for l := range lines {
+		for i := 0; i < 200; i++ {
+			x := float64(i)
+			lines[l] = append(lines[l], gopostgis.Point{X: x, Y: float64(l) + 0.3*math.Sin(x), Valid: true})
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gopostgis.SimplifyPreserveTopology(lines, 0.5)
+	}
+}
@@ -0,0 +1,91 @@
+// Package spatialsql builds parameterized SQL fragments for common postgis
+// predicates, taking go-postgis types as parameters.
+//
+// Fragments are collected by [Params], which numbers the placeholders in
+// the order they are added, so fragments can be combined freely
+//
+//	var p spatialsql.Params
+//	where := p.DWithin("location", spatialsql.Geography, point, 500)
+//	order := p.KNN("location", spatialsql.Geography, point)
+//	rows, err := db.Query("SELECT id FROM drivers WHERE "+where+" ORDER BY "+order+" LIMIT 10", p.Args()...)
+//
+// Column names are written as is, they must never come from user input.
+package spatialsql
+
+import (
+	"database/sql/driver"
+	"strconv"
+)
+
+// Type of the column a predicate is applied to.
+type Type int
+
+const (
+	// Geometry columns, distances are in the units of the SRID.
+	Geometry Type = iota
+
+	// Geography columns, distances are in meters.
+	Geography
+)
+
+func (t Type) String() string {
+	if t == Geography {
+		return "geography"
+	}
+
+	return "geometry"
+}
+
+// Params collects the arguments of a query. The zero value is ready to use.
+type Params struct {
+	args []any
+}
+
+// Add appends v to the arguments and returns its placeholder.
+func (p *Params) Add(v any) string {
+	p.args = append(p.args, v)
+	return "$" + strconv.Itoa(len(p.args))
+}
+
+// Args returns the arguments in placeholder order.
+func (p *Params) Args() []any {
+	return p.args
+}
+
+// Adds g as an argument cast to the type of the column.
+func (p *Params) geometry(t Type, g driver.Valuer) string {
+	return p.Add(g) + "::" + t.String()
+}
+
+// DWithin returns ST_DWithin(column, g, distance), distance is in meters
+// for geography columns.
+func (p *Params) DWithin(column string, t Type, g driver.Valuer, distance float64) string {
+	return "ST_DWithin(" + column + ", " + p.geometry(t, g) + ", " + p.Add(distance) + ")"
+}
+
+// Intersects returns ST_Intersects(column, g).
+func (p *Params) Intersects(column string, t Type, g driver.Valuer) string {
+	return "ST_Intersects(" + column + ", " + p.geometry(t, g) + ")"
+}
+
+// Contains returns ST_Contains(column, g). Geography has no ST_Contains,
+// ST_Covers is used instead, which also accepts g on the boundary.
+func (p *Params) Contains(column string, t Type, g driver.Valuer) string {
+	fn := "ST_Contains("
+	if t == Geography {
+		fn = "ST_Covers("
+	}
+
+	return fn + column + ", " + p.geometry(t, g) + ")"
+}
+
+// BBox returns column && g, true when the bounding boxes intersect.
+func (p *Params) BBox(column string, t Type, g driver.Valuer) string {
+	return column + " && " + p.geometry(t, g)
+}
+
+// KNN returns column <-> g for ORDER BY clauses, ordering rows by
+// distance to g using the spatial index.
+func (p *Params) KNN(column string, t Type, g driver.Valuer) string {
+	return column + " <-> " + p.geometry(t, g)
+}
@@ -0,0 +1,97 @@
+package spatialsql_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+	"github.com/asif-mahmud/go-postgis/spatialsql"
+)
+
+func TestParams(t *testing.T) {
+	point := gopostgis.PointS{SRID: 4326, X: 90.4, Y: 23.8, Valid: true}
+
+	cases := []struct {
+		fragment func(p *spatialsql.Params) string
+		expected string
+		args     []any
+	}{
+		{
+			func(p *spatialsql.Params) string { return p.DWithin("location", spatialsql.Geography, point, 500) },
+			"ST_DWithin(location, $1::geography, $2)",
+			[]any{point, 500.0},
+		},
+		{
+			func(p *spatialsql.Params) string { return p.DWithin("d.geom", spatialsql.Geometry, point, 0.5) },
+			"ST_DWithin(d.geom, $1::geometry, $2)",
+			[]any{point, 0.5},
+		},
+		{
+			func(p *spatialsql.Params) string { return p.Intersects("geom", spatialsql.Geometry, point) },
+			"ST_Intersects(geom, $1::geometry)",
+			[]any{point},
+		},
+		{
+			func(p *spatialsql.Params) string { return p.Contains("area", spatialsql.Geometry, point) },
+			"ST_Contains(area, $1::geometry)",
+			[]any{point},
+		},
+		{
+			func(p *spatialsql.Params) string { return p.Contains("area", spatialsql.Geography, point) },
+			"ST_Covers(area, $1::geography)",
+			[]any{point},
+		},
+		{
+			func(p *spatialsql.Params) string { return p.BBox("geom", spatialsql.Geometry, point) },
+			"geom && $1::geometry",
+			[]any{point},
+		},
+		{
+			func(p *spatialsql.Params) string { return p.KNN("location", spatialsql.Geography, point) },
+			"location <-> $1::geography",
+			[]any{point},
+		},
+	}
+
+	for _, c := range cases {
+		var p spatialsql.Params
+		found := c.fragment(&p)
+		if found != c.expected {
+			t.Error("expected:", c.expected, "found:", found)
+		}
+		if !reflect.DeepEqual(p.Args(), c.args) {
+			t.Error("expected:", c.args, "found:", p.Args())
+		}
+	}
+}
+
+func TestParamsNumbering(t *testing.T) {
+	var p spatialsql.Params
+	status := p.Add("available")
+	point := gopostgis.Point{X: 1, Y: 2, Valid: true}
+	where := p.BBox("geom", spatialsql.Geometry, point)
+	order := p.KNN("geom", spatialsql.Geometry, point)
+
+	expected := "status = $1 AND geom && $2::geometry ORDER BY geom <-> $3::geometry"
+	if found := "status = " + status + " AND " + where + " ORDER BY " + order; found != expected {
+		t.Error("expected:", expected, "found:", found)
+	}
+	if len(p.Args()) != 3 {
+		t.Error("expected: 3 found:", len(p.Args()))
+	}
+}
+
+func ExampleParams() {
+	var p spatialsql.Params
+	point := gopostgis.PointS{SRID: 4326, X: 90.4, Y: 23.8, Valid: true}
+
+	query := "SELECT id FROM drivers WHERE " + p.DWithin("location", spatialsql.Geography, point, 500) +
+		" ORDER BY " + p.KNN("location", spatialsql.Geography, point) + " LIMIT 10"
+
+	fmt.Println(query)
+	fmt.Println(len(p.Args()))
+	// Output:
+	// SELECT id FROM drivers WHERE ST_DWithin(location, $1::geography, $2) ORDER BY location <-> $3::geography LIMIT 10
+	// 3
+}
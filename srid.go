@@ -0,0 +1,208 @@
+package gopostgis
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrUnknownSRID is returned by [ValidateSRID] when the SRID is not
+	// registered and the validation mode requires known SRIDs.
+	ErrUnknownSRID = errors.New("unknown srid")
+
+	// ErrOutOfBounds is returned by [ValidateSRID] when the coordinates
+	// fall outside the bounds of the registered SRID.
+	ErrOutOfBounds = errors.New("coordinates out of srid bounds")
+)
+
+// SRIDInfo holds metadata of a spatial reference system.
+// Bounds are expressed in the units of the reference system.
+type SRIDInfo struct {
+	SRID       uint32
+	Name       string
+	Units      string
+	Geographic bool
+	MinX       float64
+	MinY       float64
+	MaxX       float64
+	MaxY       float64
+}
+
+// Contains reports whether (x, y) lies within the bounds of the reference system.
+func (s SRIDInfo) Contains(x, y float64) bool {
+	return x >= s.MinX && x <= s.MaxX && y >= s.MinY && y <= s.MaxY
+}
+
+// SRIDValidation controls how Value() of SRID aware types validates
+// the SRID before writing to the database.
+type SRIDValidation int
+
+const (
+	// SRIDValidationNone performs no validation. This is the default.
+	SRIDValidationNone SRIDValidation = iota
+
+	// SRIDValidationKnown rejects SRIDs which are not registered.
+	SRIDValidationKnown
+
+	// SRIDValidationBounds rejects SRIDs which are not registered and
+	// coordinates which are out of bounds of the registered SRID.
+	SRIDValidationBounds
+)
+
+type sridRegistry struct {
+	mu         sync.RWMutex
+	infos      map[uint32]SRIDInfo
+	validation SRIDValidation
+}
+
+var registry = newSRIDRegistry()
+
+func newSRIDRegistry() *sridRegistry {
+	r := &sridRegistry{
+		infos: make(map[uint32]SRIDInfo),
+	}
+
+	wgs84 := SRIDInfo{
+		Units:      "degree",
+		Geographic: true,
+		MinX:       -180,
+		MinY:       -90,
+		MaxX:       180,
+		MaxY:       90,
+	}
+	for srid, name := range map[uint32]string{
+		4326: "WGS 84",
+		4269: "NAD83",
+		4258: "ETRS89",
+		4283: "GDA94",
+		7844: "GDA2020",
+		4674: "SIRGAS 2000",
+	} {
+		info := wgs84
+		info.SRID = srid
+		info.Name = name
+		r.infos[srid] = info
+	}
+
+	r.infos[3857] = SRIDInfo{
+		SRID:  3857,
+		Name:  "WGS 84 / Pseudo-Mercator",
+		Units: "metre",
+		MinX:  -20037508.342789244,
+		MinY:  -20048966.1040146,
+		MaxX:  20037508.342789244,
+		MaxY:  20048966.1040146,
+	}
+	r.infos[3395] = SRIDInfo{
+		SRID:  3395,
+		Name:  "WGS 84 / World Mercator",
+		Units: "metre",
+		MinX:  -20037508.342789244,
+		MinY:  -15496570.739723722,
+		MaxX:  20037508.342789244,
+		MaxY:  18764656.231380563,
+	}
+	r.infos[27700] = SRIDInfo{
+		SRID:  27700,
+		Name:  "OSGB36 / British National Grid",
+		Units: "metre",
+		MinX:  0,
+		MinY:  0,
+		MaxX:  700000,
+		MaxY:  1300000,
+	}
+	r.infos[2154] = SRIDInfo{
+		SRID:  2154,
+		Name:  "RGF93 v1 / Lambert-93",
+		Units: "metre",
+		MinX:  -378305.81,
+		MinY:  6093283.21,
+		MaxX:  1212610.74,
+		MaxY:  7186901.68,
+	}
+
+	// WGS 84 / UTM zones, north (326xx) and south (327xx)
+	for zone := uint32(1); zone <= 60; zone++ {
+		r.infos[32600+zone] = SRIDInfo{
+			SRID:  32600 + zone,
+			Name:  fmt.Sprintf("WGS 84 / UTM zone %dN", zone),
+			Units: "metre",
+			MinX:  166021.44,
+			MinY:  0,
+			MaxX:  833978.56,
+			MaxY:  9329005.18,
+		}
+		r.infos[32700+zone] = SRIDInfo{
+			SRID:  32700 + zone,
+			Name:  fmt.Sprintf("WGS 84 / UTM zone %dS", zone),
+			Units: "metre",
+			MinX:  166021.44,
+			MinY:  1116915.04,
+			MaxX:  833978.56,
+			MaxY:  10000000,
+		}
+	}
+
+	return r
+}
+
+// RegisterSRID adds or replaces a spatial reference system in the registry.
+// SRID 0 is reserved by postgis for unknown SRID and cannot be registered.
+func RegisterSRID(info SRIDInfo) error {
+	if info.SRID == 0 {
+		return fmt.Errorf("srid 0 can not be registered")
+	}
+
+	if info.MinX > info.MaxX || info.MinY > info.MaxY {
+		return fmt.Errorf("invalid bounds for srid %d", info.SRID)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.infos[info.SRID] = info
+
+	return nil
+}
+
+// LookupSRID returns the registered metadata of srid.
+func LookupSRID(srid uint32) (SRIDInfo, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	info, ok := registry.infos[srid]
+
+	return info, ok
+}
+
+// SetSRIDValidation sets the validation mode used by Value() of SRID aware
+// types, i.e. [PointS], [PointZS], [PointMS] and [PointZMS].
+func SetSRIDValidation(v SRIDValidation) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.validation = v
+}
+
+// ValidateSRID validates srid and the (x, y) coordinates according to the
+// current validation mode. SRID 0 (unknown) is always accepted.
+func ValidateSRID(srid uint32, x, y float64) error {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if registry.validation == SRIDValidationNone || srid == 0 {
+		return nil
+	}
+
+	info, ok := registry.infos[srid]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownSRID, srid)
+	}
+
+	if registry.validation == SRIDValidationBounds && !info.Contains(x, y) {
+		return fmt.Errorf("%w: %g %g not in srid %d", ErrOutOfBounds, x, y, srid)
+	}
+
+	return nil
+}
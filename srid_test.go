@@ -0,0 +1,103 @@
+package gopostgis_test
+
+import (
+	"errors"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestSRID(t *testing.T) {
+	defer gopostgis.SetSRIDValidation(gopostgis.SRIDValidationNone)
+
+	t.Run("lookup", func(t *testing.T) {
+		info, ok := gopostgis.LookupSRID(4326)
+		if !ok {
+			t.Fatal("4326 should be registered")
+		}
+		if !info.Geographic || info.Units != "degree" || info.Name != "WGS 84" {
+			t.Error("unexpected metadata:", info)
+		}
+
+		info, ok = gopostgis.LookupSRID(32633)
+		if !ok {
+			t.Fatal("32633 should be registered")
+		}
+		if info.Geographic || info.Name != "WGS 84 / UTM zone 33N" {
+			t.Error("unexpected metadata:", info)
+		}
+
+		if _, ok := gopostgis.LookupSRID(4362); ok {
+			t.Error("4362 should not be registered")
+		}
+	})
+
+	t.Run("register", func(t *testing.T) {
+		info := gopostgis.SRIDInfo{
+			SRID:  910001,
+			Name:  "Local grid",
+			Units: "metre",
+			MinX:  0,
+			MinY:  0,
+			MaxX:  1000,
+			MaxY:  1000,
+		}
+		if e := gopostgis.RegisterSRID(info); e != nil {
+			t.Error(e)
+		}
+		found, ok := gopostgis.LookupSRID(910001)
+		if !ok || found != info {
+			t.Error("expected:", info, "found:", found)
+		}
+
+		if e := gopostgis.RegisterSRID(gopostgis.SRIDInfo{}); e == nil {
+			t.Error("srid 0 should not be registered")
+		}
+
+		info.SRID = 910002
+		info.MinX = 2000
+		if e := gopostgis.RegisterSRID(info); e == nil {
+			t.Error("invalid bounds should not be registered")
+		}
+	})
+
+	t.Run("validation none", func(t *testing.T) {
+		gopostgis.SetSRIDValidation(gopostgis.SRIDValidationNone)
+		p := gopostgis.PointS{SRID: 4362, X: 10, Y: 20, Valid: true}
+		if _, e := p.Value(); e != nil {
+			t.Error(e)
+		}
+	})
+
+	t.Run("validation known", func(t *testing.T) {
+		gopostgis.SetSRIDValidation(gopostgis.SRIDValidationKnown)
+		p := gopostgis.PointS{SRID: 4362, X: 10, Y: 20, Valid: true}
+		if _, e := p.Value(); !errors.Is(e, gopostgis.ErrUnknownSRID) {
+			t.Error("expected:", gopostgis.ErrUnknownSRID, "found:", e)
+		}
+
+		p.SRID = 4326
+		p.X = 200
+		if _, e := p.Value(); e != nil {
+			t.Error(e)
+		}
+
+		p.SRID = 0
+		if _, e := p.Value(); e != nil {
+			t.Error(e)
+		}
+	})
+
+	t.Run("validation bounds", func(t *testing.T) {
+		gopostgis.SetSRIDValidation(gopostgis.SRIDValidationBounds)
+		p := gopostgis.PointZMS{SRID: 4326, X: 200, Y: 20, Valid: true}
+		if _, e := p.Value(); !errors.Is(e, gopostgis.ErrOutOfBounds) {
+			t.Error("expected:", gopostgis.ErrOutOfBounds, "found:", e)
+		}
+
+		p.X = 10
+		if _, e := p.Value(); e != nil {
+			t.Error(e)
+		}
+	})
+}
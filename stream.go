@@ -0,0 +1,257 @@
+package gopostgis
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Maximum nesting of collections accepted by [EWKBReader].
+const maxEWKBDepth = 64
+
+// EWKBHeader describes a geometry decoded by [EWKBReader].
+type EWKBHeader struct {
+	// Geometry type without any flag, i.e. one of the WKB* constants.
+	Type    uint32
+	HasZ    bool
+	HasM    bool
+	HasSRID bool
+	SRID    uint32
+}
+
+// Dims returns the number of coordinates of each point of the geometry.
+func (h EWKBHeader) Dims() int {
+	n := 2
+	if h.HasZ {
+		n++
+	}
+	if h.HasM {
+		n++
+	}
+
+	return n
+}
+
+// EWKBHandler receives a geometry piece by piece from [EWKBReader].
+// Returning an error from any of the methods stops the reader.
+type EWKBHandler interface {
+	// Called when a geometry starts. Members of multi geometries and
+	// collections are reported between the Begin/End calls of their parent.
+	BeginGeometry(h EWKBHeader) error
+
+	// Called when a sequence of n points starts, i.e. a linestring or
+	// a ring of a polygon.
+	BeginRing(n uint32) error
+
+	// Called for every point. coords is reused between calls, copy it
+	// to retain the values.
+	Coordinate(coords []float64) error
+
+	// Called when a sequence of points ends.
+	EndRing() error
+
+	// Called when a geometry ends.
+	EndGeometry() error
+}
+
+// EWKBCoordinateFunc is an [EWKBHandler] which only cares about coordinates.
+type EWKBCoordinateFunc func(coords []float64) error
+
+// BeginGeometry implements EWKBHandler.
+func (f EWKBCoordinateFunc) BeginGeometry(EWKBHeader) error { return nil }
+
+// BeginRing implements EWKBHandler.
+func (f EWKBCoordinateFunc) BeginRing(uint32) error { return nil }
+
+// Coordinate implements EWKBHandler.
+func (f EWKBCoordinateFunc) Coordinate(coords []float64) error { return f(coords) }
+
+// EndRing implements EWKBHandler.
+func (f EWKBCoordinateFunc) EndRing() error { return nil }
+
+// EndGeometry implements EWKBHandler.
+func (f EWKBCoordinateFunc) EndGeometry() error { return nil }
+
+// EWKBReader decodes EWKB geometries from an io.Reader and reports them
+// incrementally to an [EWKBHandler]. Memory usage does not depend on the
+// size of the geometry, so it is suitable for geometries with millions
+// of vertices.
+// Instance of this struct should be initialized by [NewEWKBReader] or
+// [NewHexEWKBReader] function.
+type EWKBReader struct {
+	r      *bufio.Reader
+	buf    [8]byte
+	coords [4]float64
+}
+
+// Creates an [EWKBReader] over binary EWKB data.
+func NewEWKBReader(r io.Reader) *EWKBReader {
+	return &EWKBReader{
+		r: bufio.NewReader(r),
+	}
+}
+
+// Creates an [EWKBReader] over hex encoded EWKB data, as returned
+// by postgresql drivers for geometry columns.
+func NewHexEWKBReader(r io.Reader) *EWKBReader {
+	return NewEWKBReader(hex.NewDecoder(r))
+}
+
+// Read decodes the next geometry and reports it to h.
+// It returns io.EOF if there is no more geometry to read.
+func (r *EWKBReader) Read(h EWKBHandler) error {
+	if _, err := r.r.Peek(1); err != nil {
+		return err
+	}
+
+	return r.readGeometry(h, 0)
+}
+
+func (r *EWKBReader) readGeometry(h EWKBHandler, depth int) error {
+	if depth > maxEWKBDepth {
+		return fmt.Errorf("geometry nested too deep")
+	}
+
+	order, err := r.r.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+
+	var bo binary.ByteOrder
+	switch order {
+	case 0x01:
+		bo = binary.LittleEndian
+
+	case 0x00:
+		bo = binary.BigEndian
+
+	default:
+		return fmt.Errorf("unknown byteorder. got: %v", order)
+	}
+
+	t, err := r.readUint32(bo)
+	if err != nil {
+		return err
+	}
+
+	header := EWKBHeader{
+		Type:    baseType(t),
+		HasZ:    t&ewkbZFlag != 0,
+		HasM:    t&ewkbMFlag != 0,
+		HasSRID: t&ewkbSRIDFlag != 0,
+	}
+
+	// ISO WKB dimensions
+	switch (t &^ (ewkbZFlag | ewkbMFlag | ewkbSRIDFlag)) / 1000 {
+	case 1:
+		header.HasZ = true
+	case 2:
+		header.HasM = true
+	case 3:
+		header.HasZ = true
+		header.HasM = true
+	}
+
+	if header.HasSRID {
+		if header.SRID, err = r.readUint32(bo); err != nil {
+			return err
+		}
+	}
+
+	if err := h.BeginGeometry(header); err != nil {
+		return err
+	}
+
+	dims := header.Dims()
+
+	switch header.Type {
+	case WKBPoint:
+		if err := r.readCoordinate(h, bo, dims); err != nil {
+			return err
+		}
+
+	case WKBLineString, WKBCircularString:
+		if err := r.readRing(h, bo, dims); err != nil {
+			return err
+		}
+
+	case WKBPolygon, WKBTriangle:
+		n, err := r.readUint32(bo)
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i < n; i++ {
+			if err := r.readRing(h, bo, dims); err != nil {
+				return err
+			}
+		}
+
+	case WKBMultiPoint, WKBMultiLineString, WKBMultiPolygon, WKBGeometryCollection,
+		WKBCompoundCurve, WKBCurvePolygon, WKBMultiCurve, WKBMultiSurface,
+		WKBPolyhedralSurface, WKBTIN:
+		n, err := r.readUint32(bo)
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i < n; i++ {
+			if err := r.readGeometry(h, depth+1); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("geometry type not supported. got: %v", t)
+	}
+
+	return h.EndGeometry()
+}
+
+func (r *EWKBReader) readRing(h EWKBHandler, bo binary.ByteOrder, dims int) error {
+	n, err := r.readUint32(bo)
+	if err != nil {
+		return err
+	}
+
+	if err := h.BeginRing(n); err != nil {
+		return err
+	}
+
+	for i := uint32(0); i < n; i++ {
+		if err := r.readCoordinate(h, bo, dims); err != nil {
+			return err
+		}
+	}
+
+	return h.EndRing()
+}
+
+func (r *EWKBReader) readCoordinate(h EWKBHandler, bo binary.ByteOrder, dims int) error {
+	for i := 0; i < dims; i++ {
+		if _, err := io.ReadFull(r.r, r.buf[:8]); err != nil {
+			return noEOF(err)
+		}
+		r.coords[i] = math.Float64frombits(bo.Uint64(r.buf[:8]))
+	}
+
+	return h.Coordinate(r.coords[:dims])
+}
+
+func (r *EWKBReader) readUint32(bo binary.ByteOrder) (uint32, error) {
+	if _, err := io.ReadFull(r.r, r.buf[:4]); err != nil {
+		return 0, noEOF(err)
+	}
+
+	return bo.Uint32(r.buf[:4]), nil
+}
+
+// Once a geometry has started, running out of data is unexpected.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
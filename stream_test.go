@@ -0,0 +1,165 @@
+package gopostgis_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+// Records the calls made by EWKBReader.
+type recordingHandler struct {
+	events []string
+}
+
+func (r *recordingHandler) BeginGeometry(h gopostgis.EWKBHeader) error {
+	r.events = append(r.events, fmt.Sprintf("begin %d z=%v m=%v srid=%d", h.Type, h.HasZ, h.HasM, h.SRID))
+	return nil
+}
+
+func (r *recordingHandler) BeginRing(n uint32) error {
+	r.events = append(r.events, fmt.Sprintf("ring %d", n))
+	return nil
+}
+
+func (r *recordingHandler) Coordinate(coords []float64) error {
+	r.events = append(r.events, fmt.Sprint(coords))
+	return nil
+}
+
+func (r *recordingHandler) EndRing() error {
+	r.events = append(r.events, "end ring")
+	return nil
+}
+
+func (r *recordingHandler) EndGeometry() error {
+	r.events = append(r.events, "end")
+	return nil
+}
+
+func testStream(s string, expected []string, t *testing.T) {
+	r := gopostgis.NewHexEWKBReader(strings.NewReader(s))
+	var h recordingHandler
+	if e := r.Read(&h); e != nil {
+		t.Fatal(e)
+	}
+
+	if strings.Join(expected, "\n") != strings.Join(h.events, "\n") {
+		t.Error("expected:", expected, "found:", h.events)
+	}
+
+	if e := r.Read(&h); e != io.EOF {
+		t.Error("expected:", io.EOF, "found:", e)
+	}
+}
+
+func TestStream(t *testing.T) {
+	t.Run("polygon", func(t *testing.T) {
+		// SRID=4326;POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))
+		s := "0103000020E61000000200000005000000000000000000000000000000000000000000000000001040000000000000000000000000000010400000000000001040000000000000000000000000000010400000000000000000000000000000000004000000000000000000F03F000000000000F03F0000000000000040000000000000F03F00000000000000400000000000000040000000000000F03F000000000000F03F"
+		testStream(s, []string{
+			"begin 3 z=false m=false srid=4326",
+			"ring 5", "[0 0]", "[4 0]", "[4 4]", "[0 4]", "[0 0]", "end ring",
+			"ring 4", "[1 1]", "[2 1]", "[2 2]", "[1 1]", "end ring",
+			"end",
+		}, t)
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		// GEOMETRYCOLLECTION Z(POINT Z(1 2 3),LINESTRING Z(0 0 0,1 1 1))
+		// with the linestring in big endian
+		s := "0107000080020000000101000080000000000000F03F000000000000004000000000000008400080000002000000020000000000000000000000000000000000000000000000003FF00000000000003FF00000000000003FF0000000000000"
+		testStream(s, []string{
+			"begin 7 z=true m=false srid=0",
+			"begin 1 z=true m=false srid=0", "[1 2 3]", "end",
+			"begin 2 z=true m=false srid=0", "ring 2", "[0 0 0]", "[1 1 1]", "end ring", "end",
+			"end",
+		}, t)
+	})
+
+	t.Run("iso", func(t *testing.T) {
+		// POINT ZM(1 2 3 4) in ISO WKB
+		s := "01B90B0000000000000000F03F000000000000004000000000000008400000000000001040"
+		testStream(s, []string{
+			"begin 1 z=true m=true srid=0", "[1 2 3 4]", "end",
+		}, t)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		// POINT(10 20) missing the last byte
+		s := "0101000000000000000000244000000000000034"
+		r := gopostgis.NewHexEWKBReader(strings.NewReader(s))
+		var h recordingHandler
+		if e := r.Read(&h); e != io.ErrUnexpectedEOF {
+			t.Error("expected:", io.ErrUnexpectedEOF, "found:", e)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		// POINT(10 20)
+		s := "010100000000000000000024400000000000003440"
+		stop := errors.New("stop")
+		r := gopostgis.NewHexEWKBReader(strings.NewReader(s))
+		e := r.Read(gopostgis.EWKBCoordinateFunc(func(coords []float64) error {
+			return stop
+		}))
+		if e != stop {
+			t.Error("expected:", stop, "found:", e)
+		}
+	})
+}
+
+// Generates a hex encoded linestring of n points without materializing it.
+type lineStringSource struct {
+	header string
+	n      int
+	i      int
+}
+
+func (l *lineStringSource) Read(p []byte) (int, error) {
+	// POINT(0 0) coordinates as little endian doubles
+	const point = "0000000000000000" + "0000000000000000"
+	w := 0
+	for w < len(p) {
+		if l.header != "" {
+			c := copy(p[w:], l.header)
+			l.header = l.header[c:]
+			w += c
+			continue
+		}
+		if l.i >= l.n*len(point) {
+			break
+		}
+		p[w] = point[l.i%len(point)]
+		l.i++
+		w++
+	}
+	if w == 0 {
+		return 0, io.EOF
+	}
+	return w, nil
+}
+
+func BenchmarkStream(b *testing.B) {
+	const n = 1000000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		// LINESTRING of n points
+		src := &lineStringSource{header: "0102000000" + "40420F00", n: n}
+		count := 0
+		r := gopostgis.NewHexEWKBReader(src)
+		e := r.Read(gopostgis.EWKBCoordinateFunc(func(coords []float64) error {
+			count++
+			return nil
+		}))
+		if e != nil {
+			b.Fatal(e)
+		}
+		if count != n {
+			b.Fatal("expected:", n, "found:", count)
+		}
+	}
+}
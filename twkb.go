@@ -0,0 +1,610 @@
+package gopostgis
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// TWKB (Tiny WKB) encoding, compatible with postgis ST_AsTWKB and
+// ST_GeomFromTWKB.
+// reference - https://github.com/TWKB/Specification/blob/master/twkb.md
+// TWKB does not carry SRID, so SRID of decoded points is always 0.
+
+// TWKBOptions controls TWKB encoding, same as the parameters of ST_AsTWKB.
+type TWKBOptions struct {
+	// Number of decimal digits of X and Y, between -7 and 7.
+	Precision int
+
+	// Number of decimal digits of Z and M, between 0 and 7.
+	PrecisionZ int
+	PrecisionM int
+
+	// Include the size of the geometry.
+	Size bool
+
+	// Include the bounding box of the geometry.
+	BBox bool
+}
+
+func (o TWKBOptions) validate() error {
+	if o.Precision < -7 || o.Precision > 7 {
+		return fmt.Errorf("twkb precision out of range. got: %d", o.Precision)
+	}
+
+	if o.PrecisionZ < 0 || o.PrecisionZ > 7 || o.PrecisionM < 0 || o.PrecisionM > 7 {
+		return fmt.Errorf("twkb z/m precision out of range. got: %d %d", o.PrecisionZ, o.PrecisionM)
+	}
+
+	return nil
+}
+
+// Metadata flags of TWKB header
+const (
+	twkbBBox        = 0x01
+	twkbSize        = 0x02
+	twkbIDList      = 0x04
+	twkbExtendedDim = 0x08
+	twkbEmpty       = 0x10
+)
+
+// Scale factors of every dimension of layout l.
+func (o TWKBOptions) scales(l pointLayout) [4]float64 {
+	var s [4]float64
+	for i, name := range l.names {
+		switch name {
+		case "Z":
+			s[i] = math.Pow10(o.PrecisionZ)
+		case "M":
+			s[i] = math.Pow10(o.PrecisionM)
+		default:
+			s[i] = math.Pow10(o.Precision)
+		}
+	}
+
+	return s
+}
+
+// Encodes geometry g of type t with points of layout l. g holds the parts
+// of a multi geometry, a single geometry being one part. A part is a list
+// of rings, a point or a line being a part of one ring.
+func encodeTWKB(l pointLayout, t uint32, g [][][]pointData, ids []int64, o TWKBOptions) ([]byte, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	if ids != nil && len(ids) != len(g) {
+		return nil, fmt.Errorf("twkb id list length mismatch. got: %d ids for %d geometries", len(ids), len(g))
+	}
+
+	scales := o.scales(l)
+	dims := l.dims()
+
+	// quantized coordinates, an empty point leaves an empty point geometry
+	parts := make([][][][4]int64, 0, len(g))
+	var lo, hi [4]int64
+	count := 0
+	for _, part := range g {
+		rings := make([][][4]int64, len(part))
+		for i, r := range part {
+			for _, p := range r {
+				p = p.withPrecision()
+				empty, err := p.check()
+				if err != nil {
+					return nil, err
+				}
+				if empty {
+					if t != WKBPoint {
+						return nil, fmt.Errorf("twkb geometry can not have empty points")
+					}
+					continue
+				}
+
+				var c [4]int64
+				for k := 0; k < dims; k++ {
+					c[k] = int64(math.Round(p.coords[k] * scales[k]))
+					if count == 0 || c[k] < lo[k] {
+						lo[k] = c[k]
+					}
+					if count == 0 || c[k] > hi[k] {
+						hi[k] = c[k]
+					}
+				}
+				rings[i] = append(rings[i], c)
+				count++
+			}
+		}
+		if t != WKBPoint || count > 0 {
+			parts = append(parts, rings)
+		}
+	}
+
+	var body []byte
+	meta := byte(0)
+	if len(parts) == 0 || (t <= WKBPolygon && len(parts[0]) == 0) || (t == WKBLineString && len(parts[0][0]) == 0) {
+		meta |= twkbEmpty
+		parts = nil
+	}
+
+	if o.BBox && count > 0 {
+		meta |= twkbBBox
+		for k := 0; k < dims; k++ {
+			body = appendVarint(body, lo[k])
+			body = appendVarint(body, hi[k]-lo[k])
+		}
+	}
+
+	base := t
+	if t >= WKBMultiPoint {
+		base = t - 3
+		if len(parts) > 0 {
+			body = appendUvarint(body, uint64(len(parts)))
+			if ids != nil {
+				meta |= twkbIDList
+				for _, id := range ids {
+					body = appendVarint(body, id)
+				}
+			}
+		}
+	}
+
+	var prev [4]int64
+	for _, part := range parts {
+		if base == WKBPolygon {
+			body = appendUvarint(body, uint64(len(part)))
+		}
+		for _, r := range part {
+			if base != WKBPoint {
+				body = appendUvarint(body, uint64(len(r)))
+			}
+			for _, c := range r {
+				for k := 0; k < dims; k++ {
+					body = appendVarint(body, c[k]-prev[k])
+				}
+				prev = c
+			}
+		}
+	}
+
+	if o.Size {
+		meta |= twkbSize
+	}
+
+	hasZ := l.flags&ewkbZFlag != 0
+	hasM := l.flags&ewkbMFlag != 0
+	if hasZ || hasM {
+		meta |= twkbExtendedDim
+	}
+
+	b := make([]byte, 0, 4+len(body))
+	b = append(b, byte(t)|zigzag4(o.Precision)<<4, meta)
+
+	if meta&twkbExtendedDim != 0 {
+		ext := byte(0)
+		if hasZ {
+			ext |= 0x01 | byte(o.PrecisionZ)<<2
+		}
+		if hasM {
+			ext |= 0x02 | byte(o.PrecisionM)<<5
+		}
+		b = append(b, ext)
+	}
+
+	if o.Size {
+		b = appendUvarint(b, uint64(len(body)))
+	}
+
+	return append(b, body...), nil
+}
+
+// Zigzag encoding of the 4 bit precision.
+func zigzag4(v int) byte {
+	if v < 0 {
+		return byte(-2*v - 1)
+	}
+
+	return byte(2 * v)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// Decodes twkb geometry b of type t into points of layout l, in the parts
+// and rings of [encodeTWKB]. An empty geometry has no parts. Coordinates
+// are matched by name, dimensions missing in l are dropped.
+func decodeTWKB(b []byte, l pointLayout, t uint32) ([][][]pointData, []int64, error) {
+	if len(b) < 2 {
+		return nil, nil, errTWKBShort
+	}
+
+	if found := uint32(b[0] & 0x0f); found != t {
+		return nil, nil, fmt.Errorf("twkb geometry type not supported. got: %v", found)
+	}
+
+	precision := int(b[0] >> 4)
+	precision = (precision >> 1) ^ -(precision & 1)
+	meta := b[1]
+	b = b[2:]
+
+	names := []string{"X", "Y"}
+	scales := []float64{math.Pow10(precision), math.Pow10(precision)}
+
+	if meta&twkbExtendedDim != 0 {
+		if len(b) < 1 {
+			return nil, nil, errTWKBShort
+		}
+		ext := b[0]
+		b = b[1:]
+		if ext&0x01 != 0 {
+			names = append(names, "Z")
+			scales = append(scales, math.Pow10(int(ext>>2&0x07)))
+		}
+		if ext&0x02 != 0 {
+			names = append(names, "M")
+			scales = append(scales, math.Pow10(int(ext>>5&0x07)))
+		}
+	}
+
+	if meta&twkbSize != 0 {
+		size, err := readUvarint(&b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if uint64(len(b)) < size {
+			return nil, nil, errTWKBShort
+		}
+		b = b[:size]
+	}
+
+	if meta&twkbEmpty != 0 {
+		return nil, nil, nil
+	}
+
+	if meta&twkbBBox != 0 {
+		for range names {
+			if _, err := readVarint(&b); err != nil {
+				return nil, nil, err
+			}
+			if _, err := readVarint(&b); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// every element takes at least a byte
+	readCount := func() (int, error) {
+		n, err := readUvarint(&b)
+		if err != nil {
+			return 0, err
+		}
+		if n > uint64(len(b)) {
+			return 0, errTWKBShort
+		}
+		return int(n), nil
+	}
+
+	var prev [4]int64
+	readPoints := func(n int) ([]pointData, error) {
+		points := make([]pointData, n)
+		for i := range points {
+			p := pointData{layout: l, valid: true}
+			for j, name := range names {
+				delta, err := readVarint(&b)
+				if err != nil {
+					return nil, err
+				}
+				prev[j] += delta
+				for k, ln := range l.names {
+					if ln == name {
+						p.coords[k] = float64(prev[j]) / scales[j]
+					}
+				}
+			}
+			points[i] = p
+		}
+		return points, nil
+	}
+
+	base, n := t, 1
+	var ids []int64
+	if t >= WKBMultiPoint {
+		var err error
+		base = t - 3
+		if n, err = readCount(); err != nil {
+			return nil, nil, err
+		}
+		if meta&twkbIDList != 0 {
+			ids = make([]int64, n)
+			for i := range ids {
+				if ids[i], err = readVarint(&b); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+
+	parts := make([][][]pointData, n)
+	for i := range parts {
+		rings := 1
+		if base == WKBPolygon {
+			var err error
+			if rings, err = readCount(); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		parts[i] = make([][]pointData, rings)
+		for j := range parts[i] {
+			points := 1
+			if base != WKBPoint {
+				var err error
+				if points, err = readCount(); err != nil {
+					return nil, nil, err
+				}
+			}
+
+			var err error
+			if parts[i][j], err = readPoints(points); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return parts, ids, nil
+}
+
+var errTWKBShort = errors.New("twkb data too short")
+
+func readUvarint(b *[]byte) (uint64, error) {
+	v, n := binary.Uvarint(*b)
+	if n <= 0 {
+		return 0, errTWKBShort
+	}
+	*b = (*b)[n:]
+
+	return v, nil
+}
+
+func readVarint(b *[]byte) (int64, error) {
+	v, n := binary.Varint(*b)
+	if n <= 0 {
+		return 0, errTWKBShort
+	}
+	*b = (*b)[n:]
+
+	return v, nil
+}
+
+// Implements MarshalTWKB for every point type.
+func (d pointData) marshalTWKB(o TWKBOptions) ([]byte, error) {
+	if !d.valid {
+		return nil, nil
+	}
+
+	return encodeTWKB(d.layout, WKBPoint, [][][]pointData{{{d}}}, nil, o)
+}
+
+// Implements UnmarshalTWKB for every point type.
+func unmarshalTWKB(b []byte, l pointLayout) (pointData, error) {
+	if b == nil {
+		return pointData{layout: l}, nil
+	}
+
+	parts, _, err := decodeTWKB(b, l, WKBPoint)
+	if err != nil {
+		return pointData{}, err
+	}
+
+	if len(parts) == 0 {
+		return pointData{layout: l, empty: true, valid: true}, nil
+	}
+
+	return parts[0][0][0], nil
+}
+
+// Point data of rings for twkb, NULL points are not allowed.
+func twkbRings[T PointType](rings [][]T) ([][]pointData, error) {
+	data := make([][]pointData, len(rings))
+	for i, r := range rings {
+		data[i] = make([]pointData, len(r))
+		for j, p := range r {
+			data[i][j] = p.data()
+			if !data[i][j].valid {
+				return nil, fmt.Errorf("twkb geometry can not have NULL points")
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// Points of type T of rings decoded from twkb.
+func fromTWKBRings[T PointType, P interface {
+	*T
+	setData(pointData)
+}](rings [][]pointData) [][]T {
+	points := make([][]T, len(rings))
+	for i, r := range rings {
+		points[i] = make([]T, len(r))
+		for j, d := range r {
+			P(&points[i][j]).setData(d)
+		}
+	}
+
+	return points
+}
+
+// MarshalTWKBMultiPoint encodes points as a TWKB multi point. If ids is not
+// nil, it must have an id for every point and is encoded as the id list,
+// same as ST_AsTWKB(geometry[], bigint[]). NULL and EMPTY points are not
+// allowed in a multi point.
+func MarshalTWKBMultiPoint[T PointType](points []T, ids []int64, o TWKBOptions) ([]byte, error) {
+	data, err := twkbRings([][]T{points})
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([][][]pointData, len(points))
+	for i, d := range data[0] {
+		parts[i] = [][]pointData{{d}}
+	}
+
+	var zero T
+	return encodeTWKB(zero.data().layout, WKBMultiPoint, parts, ids, o)
+}
+
+// UnmarshalTWKBMultiPoint decodes a TWKB multi point into points of type T
+// along with the id list, which is nil if the data does not have one.
+func UnmarshalTWKBMultiPoint[T PointType, P interface {
+	*T
+	setData(pointData)
+}](b []byte) ([]T, []int64, error) {
+	var zero T
+	parts, ids, err := decodeTWKB(b, zero.data().layout, WKBMultiPoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := make([]pointData, len(parts))
+	for i, p := range parts {
+		data[i] = p[0][0]
+	}
+
+	return fromTWKBRings[T, P]([][]pointData{data})[0], ids, nil
+}
+
+// MarshalTWKBLineString encodes line as a TWKB line string, an empty line
+// is LINESTRING EMPTY.
+func MarshalTWKBLineString[T PointType](line []T, o TWKBOptions) ([]byte, error) {
+	data, err := twkbRings([][]T{line})
+	if err != nil {
+		return nil, err
+	}
+
+	var zero T
+	return encodeTWKB(zero.data().layout, WKBLineString, [][][]pointData{data}, nil, o)
+}
+
+// UnmarshalTWKBLineString decodes a TWKB line string into points of type T.
+func UnmarshalTWKBLineString[T PointType, P interface {
+	*T
+	setData(pointData)
+}](b []byte) ([]T, error) {
+	var zero T
+	parts, _, err := decodeTWKB(b, zero.data().layout, WKBLineString)
+	if err != nil || len(parts) == 0 {
+		return nil, err
+	}
+
+	return fromTWKBRings[T, P](parts[0])[0], nil
+}
+
+// MarshalTWKBPolygon encodes polygon, the exterior ring followed by the
+// interior rings, as a TWKB polygon.
+func MarshalTWKBPolygon[T PointType](polygon [][]T, o TWKBOptions) ([]byte, error) {
+	data, err := twkbRings(polygon)
+	if err != nil {
+		return nil, err
+	}
+
+	var zero T
+	return encodeTWKB(zero.data().layout, WKBPolygon, [][][]pointData{data}, nil, o)
+}
+
+// UnmarshalTWKBPolygon decodes a TWKB polygon into rings of type T.
+func UnmarshalTWKBPolygon[T PointType, P interface {
+	*T
+	setData(pointData)
+}](b []byte) ([][]T, error) {
+	var zero T
+	parts, _, err := decodeTWKB(b, zero.data().layout, WKBPolygon)
+	if err != nil || len(parts) == 0 {
+		return nil, err
+	}
+
+	return fromTWKBRings[T, P](parts[0]), nil
+}
+
+// MarshalTWKBMultiLineString encodes lines as a TWKB multi line string,
+// with the id list ids like [MarshalTWKBMultiPoint].
+func MarshalTWKBMultiLineString[T PointType](lines [][]T, ids []int64, o TWKBOptions) ([]byte, error) {
+	data, err := twkbRings(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([][][]pointData, len(data))
+	for i, d := range data {
+		parts[i] = [][]pointData{d}
+	}
+
+	var zero T
+	return encodeTWKB(zero.data().layout, WKBMultiLineString, parts, ids, o)
+}
+
+// UnmarshalTWKBMultiLineString decodes a TWKB multi line string into lines
+// of type T along with the id list, which is nil if the data does not have
+// one.
+func UnmarshalTWKBMultiLineString[T PointType, P interface {
+	*T
+	setData(pointData)
+}](b []byte) ([][]T, []int64, error) {
+	var zero T
+	parts, ids, err := decodeTWKB(b, zero.data().layout, WKBMultiLineString)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lines := make([][]pointData, len(parts))
+	for i, p := range parts {
+		lines[i] = p[0]
+	}
+
+	return fromTWKBRings[T, P](lines), ids, nil
+}
+
+// MarshalTWKBMultiPolygon encodes polygons as a TWKB multi polygon, with
+// the id list ids like [MarshalTWKBMultiPoint].
+func MarshalTWKBMultiPolygon[T PointType](polygons [][][]T, ids []int64, o TWKBOptions) ([]byte, error) {
+	parts := make([][][]pointData, len(polygons))
+	for i, p := range polygons {
+		var err error
+		if parts[i], err = twkbRings(p); err != nil {
+			return nil, err
+		}
+	}
+
+	var zero T
+	return encodeTWKB(zero.data().layout, WKBMultiPolygon, parts, ids, o)
+}
+
+// UnmarshalTWKBMultiPolygon decodes a TWKB multi polygon into polygons of
+// type T along with the id list, which is nil if the data does not have
+// one.
+func UnmarshalTWKBMultiPolygon[T PointType, P interface {
+	*T
+	setData(pointData)
+}](b []byte) ([][][]T, []int64, error) {
+	var zero T
+	parts, ids, err := decodeTWKB(b, zero.data().layout, WKBMultiPolygon)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	polygons := make([][][]T, len(parts))
+	for i, p := range parts {
+		polygons[i] = fromTWKBRings[T, P](p)
+	}
+
+	return polygons, ids, nil
+}
@@ -0,0 +1,264 @@
+package gopostgis_test
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestTWKB(t *testing.T) {
+	t.Run("marshal", func(t *testing.T) {
+		cases := []struct {
+			point    gopostgis.Point
+			opts     gopostgis.TWKBOptions
+			expected string
+		}{
+			// ST_AsTWKB('POINT(1 2)')
+			{gopostgis.Point{X: 1, Y: 2, Valid: true}, gopostgis.TWKBOptions{}, "01000204"},
+			// ST_AsTWKB('POINT(1.1234 2.5678)', 2)
+			{gopostgis.Point{X: 1.1234, Y: 2.5678, Valid: true}, gopostgis.TWKBOptions{Precision: 2}, "4100e0018204"},
+			// ST_AsTWKB('POINT(1 2)', 0, 0, 0, true, true)
+			{gopostgis.Point{X: 1, Y: 2, Valid: true}, gopostgis.TWKBOptions{Size: true, BBox: true}, "01030602000400 0204"},
+			// ST_AsTWKB('POINT EMPTY')
+			{gopostgis.Point{Empty: true, Valid: true}, gopostgis.TWKBOptions{}, "0110"},
+		}
+		for _, c := range cases {
+			found, e := c.point.MarshalTWKB(c.opts)
+			if e != nil {
+				t.Error(e)
+			}
+			expected := strings.ReplaceAll(c.expected, " ", "")
+			if hex.EncodeToString(found) != expected {
+				t.Error("expected:", expected, "found:", hex.EncodeToString(found))
+			}
+		}
+	})
+
+	t.Run("marshal null", func(t *testing.T) {
+		found, e := gopostgis.PointS{}.MarshalTWKB(gopostgis.TWKBOptions{})
+		if e != nil || found != nil {
+			t.Error("expected: nil found:", found, e)
+		}
+	})
+
+	t.Run("marshal invalid precision", func(t *testing.T) {
+		p := gopostgis.Point{X: 1, Y: 2, Valid: true}
+		if _, e := p.MarshalTWKB(gopostgis.TWKBOptions{Precision: 8}); e == nil {
+			t.Error("precision 8 should fail")
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		p := gopostgis.PointZM{X: -71.064544, Y: 42.28787, Z: 12.5, M: 3, Valid: true}
+		opts := gopostgis.TWKBOptions{Precision: 6, PrecisionZ: 1, Size: true, BBox: true}
+		b, e := p.MarshalTWKB(opts)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		var found gopostgis.PointZM
+		if e := found.UnmarshalTWKB(b); e != nil {
+			t.Fatal(e)
+		}
+		if found != p {
+			t.Error("expected:", p, "found:", found)
+		}
+
+		// Z and M are dropped, not mixed up
+		var xy gopostgis.PointM
+		if e := xy.UnmarshalTWKB(b); e != nil {
+			t.Fatal(e)
+		}
+		if xy.X != p.X || xy.Y != p.Y || xy.M != p.M {
+			t.Error("expected:", p, "found:", xy)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		b, _ := hex.DecodeString("4100e0018204")
+		var p gopostgis.PointS
+		if e := p.UnmarshalTWKB(b); e != nil {
+			t.Fatal(e)
+		}
+		if !p.Valid || p.SRID != 0 || p.X != 1.12 || p.Y != 2.57 {
+			t.Error("unexpected point:", p)
+		}
+
+		b, _ = hex.DecodeString("0110")
+		if e := p.UnmarshalTWKB(b); e != nil {
+			t.Fatal(e)
+		}
+		if !p.Valid || !p.Empty {
+			t.Error("expected empty point found:", p)
+		}
+
+		if e := p.UnmarshalTWKB(nil); e != nil || p.Valid {
+			t.Error("expected null point found:", p, e)
+		}
+
+		b, _ = hex.DecodeString("0100")
+		if e := p.UnmarshalTWKB(b); e == nil {
+			t.Error("truncated data should fail")
+		}
+
+		b, _ = hex.DecodeString("0200020204")
+		if e := p.UnmarshalTWKB(b); e == nil {
+			t.Error("linestring should not be decoded as a point")
+		}
+	})
+
+	t.Run("multi point", func(t *testing.T) {
+		points := []gopostgis.Point{
+			{X: 1, Y: 2, Valid: true},
+			{X: 3, Y: 4, Valid: true},
+		}
+
+		// ST_AsTWKB(ARRAY['POINT(1 2)', 'POINT(3 4)'], ARRAY[10, 20])
+		expected := "04040214280204 0404"
+		found, e := gopostgis.MarshalTWKBMultiPoint(points, []int64{10, 20}, gopostgis.TWKBOptions{})
+		if e != nil {
+			t.Fatal(e)
+		}
+		if hex.EncodeToString(found) != strings.ReplaceAll(expected, " ", "") {
+			t.Error("expected:", strings.ReplaceAll(expected, " ", ""), "found:", hex.EncodeToString(found))
+		}
+
+		decoded, ids, e := gopostgis.UnmarshalTWKBMultiPoint[gopostgis.Point](found)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(decoded, points) || !reflect.DeepEqual(ids, []int64{10, 20}) {
+			t.Error("expected:", points, "found:", decoded, ids)
+		}
+
+		if _, e := gopostgis.MarshalTWKBMultiPoint(points, []int64{1}, gopostgis.TWKBOptions{}); e == nil {
+			t.Error("id list length mismatch should fail")
+		}
+	})
+	t.Run("line string", func(t *testing.T) {
+		line := []gopostgis.Point{{X: 1, Y: 1, Valid: true}, {X: 5, Y: 5, Valid: true}}
+
+		// ST_AsTWKB('LINESTRING(1 1,5 5)')
+		expected := "02000202020808"
+		found, e := gopostgis.MarshalTWKBLineString(line, gopostgis.TWKBOptions{})
+		if e != nil {
+			t.Fatal(e)
+		}
+		if hex.EncodeToString(found) != expected {
+			t.Error("expected:", expected, "found:", hex.EncodeToString(found))
+		}
+
+		decoded, e := gopostgis.UnmarshalTWKBLineString[gopostgis.Point](found)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(decoded, line) {
+			t.Error("expected:", line, "found:", decoded)
+		}
+
+		// ST_AsTWKB('LINESTRING EMPTY')
+		found, e = gopostgis.MarshalTWKBLineString([]gopostgis.Point{}, gopostgis.TWKBOptions{})
+		if e != nil || hex.EncodeToString(found) != "0210" {
+			t.Error("expected: 0210 found:", hex.EncodeToString(found), e)
+		}
+		if decoded, e := gopostgis.UnmarshalTWKBLineString[gopostgis.Point](found); e != nil || len(decoded) != 0 {
+			t.Error("expected: empty line found:", decoded, e)
+		}
+
+		if _, e := gopostgis.UnmarshalTWKBPolygon[gopostgis.Point](found); e == nil {
+			t.Error("line string should not be decoded as a polygon")
+		}
+
+		null := []gopostgis.Point{{X: 1, Y: 1, Valid: true}, {}}
+		if _, e := gopostgis.MarshalTWKBLineString(null, gopostgis.TWKBOptions{}); e == nil {
+			t.Error("NULL point should fail")
+		}
+	})
+
+	t.Run("polygon", func(t *testing.T) {
+		polygon := [][]gopostgis.PointZM{
+			{{X: 0, Y: 0, Z: 1, M: 2, Valid: true}, {X: 4, Y: 0, Z: 1, M: 2, Valid: true}, {X: 4, Y: 4, Z: 1.5, M: 2, Valid: true}, {X: 0, Y: 0, Z: 1, M: 2, Valid: true}},
+			{{X: 1, Y: 1, Valid: true}, {X: 3, Y: 1, Valid: true}, {X: 3, Y: 2, Valid: true}, {X: 1, Y: 1, Valid: true}},
+		}
+		opts := gopostgis.TWKBOptions{Precision: 1, PrecisionZ: 1, Size: true, BBox: true}
+
+		found, e := gopostgis.MarshalTWKBPolygon(polygon, opts)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		// ST_AsTWKB('POLYGON((0 0,1 0,1 1,0 0))')
+		triangle := [][]gopostgis.Point{{{X: 0, Y: 0, Valid: true}, {X: 1, Y: 0, Valid: true}, {X: 1, Y: 1, Valid: true}, {X: 0, Y: 0, Valid: true}}}
+		b, e := gopostgis.MarshalTWKBPolygon(triangle, gopostgis.TWKBOptions{})
+		if expected := "030001040000020000020101"; e != nil || hex.EncodeToString(b) != expected {
+			t.Error("expected:", expected, "found:", hex.EncodeToString(b), e)
+		}
+
+		decoded, e := gopostgis.UnmarshalTWKBPolygon[gopostgis.PointZM](found)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(decoded, polygon) {
+			t.Error("expected:", polygon, "found:", decoded)
+		}
+	})
+
+	t.Run("multi line string", func(t *testing.T) {
+		lines := [][]gopostgis.PointS{
+			{{X: 1, Y: 2, Valid: true}, {X: 3, Y: 4, Valid: true}},
+			{{X: -1, Y: 0, Valid: true}, {X: 0, Y: 0, Valid: true}, {X: 0, Y: 1, Valid: true}},
+		}
+
+		found, e := gopostgis.MarshalTWKBMultiLineString(lines, []int64{7, 8}, gopostgis.TWKBOptions{BBox: true})
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		decoded, ids, e := gopostgis.UnmarshalTWKBMultiLineString[gopostgis.PointS](found)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !reflect.DeepEqual(decoded, lines) || !reflect.DeepEqual(ids, []int64{7, 8}) {
+			t.Error("expected:", lines, "found:", decoded, ids)
+		}
+	})
+
+	t.Run("multi polygon", func(t *testing.T) {
+		polygons := [][][]gopostgis.Point{
+			{xyLine(0, 0, 2, 0, 2, 2, 0, 0)},
+			{xyLine(5, 5, 9, 5, 9, 9, 5, 9, 5, 5), xyLine(6, 6, 7, 6, 7, 7, 6, 6)},
+		}
+
+		for _, ids := range [][]int64{nil, {-3, 1 << 40}} {
+			found, e := gopostgis.MarshalTWKBMultiPolygon(polygons, ids, gopostgis.TWKBOptions{Size: true})
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			decoded, foundIDs, e := gopostgis.UnmarshalTWKBMultiPolygon[gopostgis.Point](found)
+			if e != nil {
+				t.Fatal(e)
+			}
+			if !reflect.DeepEqual(decoded, polygons) || !reflect.DeepEqual(foundIDs, ids) {
+				t.Error("expected:", polygons, ids, "found:", decoded, foundIDs)
+			}
+
+			if _, _, e := gopostgis.UnmarshalTWKBMultiPolygon[gopostgis.Point](found[:len(found)-1]); e == nil {
+				t.Error("truncated data should fail")
+			}
+		}
+
+		if _, e := gopostgis.MarshalTWKBMultiPolygon(polygons, []int64{1}, gopostgis.TWKBOptions{}); e == nil {
+			t.Error("id list length mismatch should fail")
+		}
+
+		// ST_AsTWKB('MULTIPOLYGON EMPTY')
+		found, e := gopostgis.MarshalTWKBMultiPolygon([][][]gopostgis.Point{}, nil, gopostgis.TWKBOptions{})
+		if e != nil || hex.EncodeToString(found) != "0610" {
+			t.Error("expected: 0610 found:", hex.EncodeToString(found), e)
+		}
+	})
+}
@@ -0,0 +1,381 @@
+package gopostgis
+
+import (
+	"fmt"
+	"math"
+)
+
+// ValidityCode identifies the reason of a geometry being valid or invalid.
+type ValidityCode int
+
+const (
+	// ValidityOK means the geometry is valid.
+	ValidityOK ValidityCode = iota
+
+	// ValidityInvalidCoordinate means one of the coordinates is NaN or Inf.
+	ValidityInvalidCoordinate
+
+	// ValidityTooFewPoints means a ring has less than 4 points.
+	ValidityTooFewPoints
+
+	// ValidityRingNotClosed means the first and last points of a ring differ.
+	ValidityRingNotClosed
+
+	// ValidityRingSelfIntersection means a ring intersects itself.
+	ValidityRingSelfIntersection
+
+	// ValiditySelfIntersection means a hole crosses the exterior ring.
+	ValiditySelfIntersection
+
+	// ValidityHoleOutsideShell means a hole lies outside the exterior ring.
+	ValidityHoleOutsideShell
+
+	// ValidityNestedHoles means a hole lies inside or overlaps another hole.
+	ValidityNestedHoles
+)
+
+// ValidityReason is the structured equivalent of postgis ST_IsValidReason.
+type ValidityReason struct {
+	Code ValidityCode
+
+	// Human readable description, formatted like postgis does.
+	Message string
+
+	// Offending coordinates, nil for valid geometries.
+	Location []float64
+}
+
+// Valid reports whether the reason describes a valid geometry.
+func (r ValidityReason) Valid() bool {
+	return r.Code == ValidityOK
+}
+
+// String implements fmt.Stringer
+func (r ValidityReason) String() string {
+	return r.Message
+}
+
+var validReason = ValidityReason{
+	Code:    ValidityOK,
+	Message: "Valid Geometry",
+}
+
+func coordsValidReason(coords ...float64) ValidityReason {
+	for _, c := range coords {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return ValidityReason{
+				Code:     ValidityInvalidCoordinate,
+				Message:  fmt.Sprintf("Invalid Coordinate%v", coords),
+				Location: coords,
+			}
+		}
+	}
+
+	return validReason
+}
+
+func (d pointData) validReason() ValidityReason {
+	if !d.valid || d.empty {
+		return validReason
+	}
+
+	return coordsValidReason(d.values()...)
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p Point) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p Point) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p PointS) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p PointS) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p PointZ) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p PointZ) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p PointZS) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p PointZS) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p PointM) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p PointM) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p PointMS) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p PointMS) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p PointZM) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p PointZM) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValid reports whether p is a valid geometry. NULL and EMPTY are considered valid.
+func (p PointZMS) IsValid() bool {
+	return p.ValidReason().Valid()
+}
+
+// ValidReason returns the reason of p being valid or invalid.
+func (p PointZMS) ValidReason() ValidityReason {
+	return p.data().validReason()
+}
+
+// IsValidRing reports whether ring is a valid polygon ring, closed, with
+// at least 4 points and not intersecting itself.
+func IsValidRing[T PointType](ring []T) bool {
+	return RingValidReason(ring).Valid()
+}
+
+// RingValidReason returns the reason of ring being valid or invalid.
+func RingValidReason[T PointType](ring []T) ValidityReason {
+	return PolygonValidReason([][]T{ring})
+}
+
+// IsValidPolygon reports whether polygon, the exterior ring followed by
+// the interior rings, is valid. A polygon without rings is EMPTY and
+// considered valid.
+func IsValidPolygon[T PointType](polygon [][]T) bool {
+	return PolygonValidReason(polygon).Valid()
+}
+
+// PolygonValidReason returns the reason of polygon being valid or invalid.
+// Rings must be valid, holes must not cross the exterior ring or each
+// other, lie inside the exterior ring and not inside another hole. Rings
+// may touch each other at single points.
+func PolygonValidReason[T PointType](polygon [][]T) ValidityReason {
+	rings := make([][][2]float64, len(polygon))
+	for i, ring := range polygon {
+		for _, p := range ring {
+			d := p.data()
+			if !d.valid || d.empty {
+				return ValidityReason{
+					Code:    ValidityInvalidCoordinate,
+					Message: "Invalid Coordinate: NULL or EMPTY point",
+				}
+			}
+			if r := coordsValidReason(d.values()...); !r.Valid() {
+				return r
+			}
+		}
+		// repeated points are allowed
+		rings[i] = dedupPoints(pointsXY(ring))
+	}
+
+	for _, ring := range rings {
+		if len(ring) < 4 {
+			return invalidAt(ValidityTooFewPoints, "Too few points in geometry component", ring...)
+		}
+		if !isClosed(ring) {
+			return invalidAt(ValidityRingNotClosed, "Ring not closed", ring[0])
+		}
+	}
+
+	if r := ringIntersections(rings); !r.Valid() {
+		return r
+	}
+
+	return holesValidReason(rings)
+}
+
+func invalidAt(code ValidityCode, message string, at ...[2]float64) ValidityReason {
+	r := ValidityReason{Code: code, Message: message}
+	if len(at) > 0 {
+		r.Location = []float64{at[0][0], at[0][1]}
+		r.Message = fmt.Sprintf("%s[%v %v]", message, at[0][0], at[0][1])
+	}
+
+	return r
+}
+
+// Finds rings intersecting themselves and rings crossing each other.
+// Self intersections are reported first, then holes crossing the
+// exterior ring and then holes crossing each other.
+func ringIntersections(rings [][][2]float64) ValidityReason {
+	type segment struct{ ring, index int }
+
+	var segments []segment
+	var envs []Envelope
+	for r, ring := range rings {
+		for i := 1; i < len(ring); i++ {
+			a, b := ring[i-1], ring[i]
+			segments = append(segments, segment{r, i - 1})
+			envs = append(envs, Envelope{
+				MinX: math.Min(a[0], b[0]), MinY: math.Min(a[1], b[1]),
+				MaxX: math.Max(a[0], b[0]), MaxY: math.Max(a[1], b[1]),
+			})
+		}
+	}
+
+	// first problem found of each kind, by priority
+	var found [3]*[2]float64
+	intersectingPairs(envs, func(i, j int) {
+		s, t := segments[i], segments[j]
+		p1, p2 := rings[s.ring][s.index], rings[s.ring][s.index+1]
+		q1, q2 := rings[t.ring][t.index], rings[t.ring][t.index+1]
+
+		kind := 0
+		if s.ring == t.ring {
+			n := len(rings[s.ring]) - 1
+			adjacent := t.index-s.index == 1 || (s.index == 0 && t.index == n-1)
+			if adjacent && !collinearOverlap(p1, p2, q1, q2) {
+				return
+			}
+			if !adjacent && !segmentsIntersect(p1, p2, q1, q2) {
+				return
+			}
+		} else {
+			kind = 1
+			if s.ring > 0 {
+				kind = 2
+			}
+			if !segmentsCross(p1, p2, q1, q2) && !collinearOverlap(p1, p2, q1, q2) {
+				return
+			}
+		}
+
+		if found[kind] == nil {
+			c := segmentIntersection(p1, p2, q1, q2)
+			found[kind] = &c
+		}
+	})
+
+	switch {
+	case found[0] != nil:
+		return invalidAt(ValidityRingSelfIntersection, "Ring Self-intersection", *found[0])
+	case found[1] != nil:
+		return invalidAt(ValiditySelfIntersection, "Self-intersection", *found[1])
+	case found[2] != nil:
+		return invalidAt(ValidityNestedHoles, "Holes overlap", *found[2])
+	}
+
+	return validReason
+}
+
+// Checks holes lie inside the exterior ring and outside each other, once
+// rings are known not to cross.
+func holesValidReason(rings [][][2]float64) ValidityReason {
+	if len(rings) < 2 {
+		return validReason
+	}
+
+	shell, holes := rings[0], rings[1:]
+	for _, hole := range holes {
+		if p, ok := pointOffRing(hole, shell); ok && !pointInRing(p, shell, nil) {
+			return invalidAt(ValidityHoleOutsideShell, "Hole lies outside shell", p)
+		}
+	}
+
+	envs := make([]Envelope, len(holes))
+	for i, hole := range holes {
+		envs[i] = ringEnvelope(hole)
+	}
+
+	var nested *[2]float64
+	intersectingPairs(envs, func(i, j int) {
+		if nested != nil {
+			return
+		}
+		if p, ok := pointOffRing(holes[i], holes[j]); ok && pointInRing(p, holes[j], nil) {
+			nested = &p
+		} else if p, ok := pointOffRing(holes[j], holes[i]); ok && pointInRing(p, holes[i], nil) {
+			nested = &p
+		}
+	})
+	if nested != nil {
+		return invalidAt(ValidityNestedHoles, "Holes are nested", *nested)
+	}
+
+	return validReason
+}
+
+// A point of ring a not on ring b, either a vertex or the middle of an edge.
+func pointOffRing(a, b [][2]float64) ([2]float64, bool) {
+	onRing := func(p [2]float64) bool {
+		for i := 1; i < len(b); i++ {
+			if segmentDistance(p, b[i-1], b[i]) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, p := range a {
+		if !onRing(p) {
+			return p, true
+		}
+	}
+	for i := 1; i < len(a); i++ {
+		if p := midpoint(a[i-1], a[i]); !onRing(p) {
+			return p, true
+		}
+	}
+
+	return [2]float64{}, false
+}
+
+// Whether segments p and q cross at a single point interior to both.
+func segmentsCross(p1, p2, q1, q2 [2]float64) bool {
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+// A common point of intersecting segments p and q.
+func segmentIntersection(p1, p2, q1, q2 [2]float64) [2]float64 {
+	for _, c := range [...][3][2]float64{{q1, p1, p2}, {q2, p1, p2}, {p1, q1, q2}, {p2, q1, q2}} {
+		if orientation(c[1], c[2], c[0]) == 0 && onSegment(c[0], c[1], c[2]) {
+			return c[0]
+		}
+	}
+
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	t := d1 / (d1 - d2)
+
+	return [2]float64{p1[0] + t*(p2[0]-p1[0]), p1[1] + t*(p2[1]-p1[1])}
+}
@@ -0,0 +1,147 @@
+package gopostgis_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	gopostgis "github.com/asif-mahmud/go-postgis"
+)
+
+func TestValidity(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		p := gopostgis.PointZM{X: 10, Y: 20, Z: 30, M: 40, Valid: true}
+		if !p.IsValid() {
+			t.Error("expected valid point, found:", p.ValidReason())
+		}
+		r := p.ValidReason()
+		if r.Code != gopostgis.ValidityOK || r.Message != "Valid Geometry" || r.Location != nil {
+			t.Error("unexpected reason:", r)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		p := gopostgis.Point{X: math.NaN(), Valid: false}
+		if !p.IsValid() {
+			t.Error("null should be valid, found:", p.ValidReason())
+		}
+	})
+
+	t.Run("nan", func(t *testing.T) {
+		p := gopostgis.PointS{SRID: 4326, X: math.NaN(), Y: 20, Valid: true}
+		if p.IsValid() {
+			t.Error("NaN coordinate should be invalid")
+		}
+		r := p.ValidReason()
+		expected := "Invalid Coordinate[NaN 20]"
+		if r.Code != gopostgis.ValidityInvalidCoordinate || r.Message != expected {
+			t.Error("expected:", expected, "found:", r)
+		}
+		if len(r.Location) != 2 || r.Location[1] != 20 {
+			t.Error("unexpected location:", r.Location)
+		}
+	})
+
+	t.Run("inf", func(t *testing.T) {
+		p := gopostgis.PointMS{X: 10, Y: 20, M: math.Inf(-1), Valid: true}
+		if p.IsValid() {
+			t.Error("Inf measure should be invalid")
+		}
+		if p.ValidReason().Code != gopostgis.ValidityInvalidCoordinate {
+			t.Error("unexpected reason:", p.ValidReason())
+		}
+	})
+}
+
+func TestPolygonValidity(t *testing.T) {
+	shell := xyLine(0, 0, 10, 0, 10, 10, 0, 10, 0, 0)
+
+	cases := []struct {
+		name     string
+		polygon  [][]gopostgis.Point
+		code     gopostgis.ValidityCode
+		location []float64
+	}{
+		{"empty", nil, gopostgis.ValidityOK, nil},
+		{"square", [][]gopostgis.Point{shell}, gopostgis.ValidityOK, nil},
+		{"repeated points", [][]gopostgis.Point{xyLine(0, 0, 10, 0, 10, 0, 10, 10, 0, 0)}, gopostgis.ValidityOK, nil},
+		{
+			"hole touching shell",
+			[][]gopostgis.Point{shell, xyLine(0, 5, 5, 8, 5, 2, 0, 5)},
+			gopostgis.ValidityOK, nil,
+		},
+		{
+			"holes touching",
+			[][]gopostgis.Point{shell, xyLine(2, 2, 2, 5, 5, 5, 2, 2), xyLine(5, 5, 8, 5, 8, 8, 5, 5)},
+			gopostgis.ValidityOK, nil,
+		},
+		{
+			"invalid coordinate",
+			[][]gopostgis.Point{xyLine(0, 0, math.Inf(1), 0, 10, 10, 0, 0)},
+			gopostgis.ValidityInvalidCoordinate, []float64{math.Inf(1), 0},
+		},
+		{"too few points", [][]gopostgis.Point{xyLine(0, 0, 10, 0, 0, 0)}, gopostgis.ValidityTooFewPoints, []float64{0, 0}},
+		{"too few distinct points", [][]gopostgis.Point{xyLine(0, 0, 10, 0, 10, 0, 0, 0)}, gopostgis.ValidityTooFewPoints, []float64{0, 0}},
+		{"unclosed ring", [][]gopostgis.Point{xyLine(0, 0, 10, 0, 10, 10, 0, 10)}, gopostgis.ValidityRingNotClosed, []float64{0, 0}},
+		{
+			"unclosed hole",
+			[][]gopostgis.Point{shell, xyLine(2, 2, 4, 2, 4, 4, 2, 4)},
+			gopostgis.ValidityRingNotClosed, []float64{2, 2},
+		},
+		{"bow tie", [][]gopostgis.Point{xyLine(0, 0, 10, 10, 10, 0, 0, 10, 0, 0)}, gopostgis.ValidityRingSelfIntersection, []float64{5, 5}},
+		{"spike", [][]gopostgis.Point{xyLine(0, 0, 10, 0, 10, 10, 10, 5, 0, 0)}, gopostgis.ValidityRingSelfIntersection, []float64{10, 5}},
+		{"ring touching itself", [][]gopostgis.Point{xyLine(0, 0, 10, 0, 5, 5, 10, 10, 0, 10, 5, 5, 0, 0)}, gopostgis.ValidityRingSelfIntersection, []float64{5, 5}},
+		{
+			"hole crossing shell",
+			[][]gopostgis.Point{shell, xyLine(5, 5, 15, 5, 15, 8, 5, 8, 5, 5)},
+			gopostgis.ValiditySelfIntersection, []float64{10, 5},
+		},
+		{
+			"hole outside shell",
+			[][]gopostgis.Point{shell, xyLine(20, 20, 22, 20, 22, 22, 20, 20)},
+			gopostgis.ValidityHoleOutsideShell, []float64{20, 20},
+		},
+		{
+			"nested holes",
+			[][]gopostgis.Point{shell, xyLine(1, 1, 9, 1, 9, 9, 1, 9, 1, 1), xyLine(3, 3, 5, 3, 5, 5, 3, 3)},
+			gopostgis.ValidityNestedHoles, []float64{3, 3},
+		},
+		{
+			"overlapping holes",
+			[][]gopostgis.Point{shell, xyLine(1, 1, 5, 1, 5, 5, 1, 5, 1, 1), xyLine(3, 3, 8, 3, 8, 8, 3, 8, 3, 3)},
+			gopostgis.ValidityNestedHoles, nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := gopostgis.PolygonValidReason(c.polygon)
+			if r.Code != c.code {
+				t.Error("expected:", c.code, "found:", r)
+			}
+			if c.location != nil && !reflect.DeepEqual(r.Location, c.location) {
+				t.Error("expected:", c.location, "found:", r.Location)
+			}
+			if valid := gopostgis.IsValidPolygon(c.polygon); valid != (c.code == gopostgis.ValidityOK) {
+				t.Error("expected:", c.code == gopostgis.ValidityOK, "found:", valid)
+			}
+		})
+	}
+
+	t.Run("ring", func(t *testing.T) {
+		if !gopostgis.IsValidRing(shell) {
+			t.Error("expected: valid found:", gopostgis.RingValidReason(shell))
+		}
+		r := gopostgis.RingValidReason(xyLine(0, 0, 10, 10, 10, 0, 0, 10, 0, 0))
+		if expected := "Ring Self-intersection[5 5]"; r.Message != expected {
+			t.Error("expected:", expected, "found:", r.Message)
+		}
+	})
+
+	t.Run("null point", func(t *testing.T) {
+		ring := []gopostgis.PointS{{SRID: 4326, Valid: true}, {SRID: 4326}, {SRID: 4326, X: 1, Valid: true}, {SRID: 4326, Valid: true}}
+		if r := gopostgis.RingValidReason(ring); r.Code != gopostgis.ValidityInvalidCoordinate {
+			t.Error("expected:", gopostgis.ValidityInvalidCoordinate, "found:", r)
+		}
+	})
+}